@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/KUCSEPotato/locker-server/internal/bulk"
+	"github.com/KUCSEPotato/locker-server/internal/cache"
+	"github.com/KUCSEPotato/locker-server/internal/db"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+)
+
+// `server import <locations|lockers|roster> <file.csv|-> [--dry-run] [--replace]`
+// - 관리자 API(POST /admin/import/:kind)와 같은 검증/반영 코드를 DB_URL에 직접 실행한다.
+// - 종료 코드: 0 성공, 1 행 에러(아무것도 반영 안 됨), 2 사용법/실행 오류
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "validate and roll back instead of committing")
+	replace := fs.Bool("replace", false, "(roster) remove students that are not in the file")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: server import <locations|lockers|roster> <file.csv|-> [--dry-run] [--replace]")
+		fs.PrintDefaults()
+	}
+
+	// 플래그는 위치 인자 앞/뒤 어디에 와도 된다
+	var positional []string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	kind, err := bulk.ParseKind(positional[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	var src io.Reader = os.Stdin
+	if positional[1] != "-" {
+		f, err := os.Open(positional[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer f.Close()
+		src = f
+	}
+
+	ctx := context.Background()
+	pool := db.NewPool(ctx)
+	defer pool.Close()
+
+	rep, err := bulk.Import(ctx, pool, kind, src, bulk.Options{DryRun: *dryRun, Replace: *replace})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		return 2
+	}
+
+	for _, e := range rep.Errors {
+		if e.Column != "" {
+			fmt.Printf("line %d [%s]: %s\n", e.Line, e.Column, e.Message)
+		} else {
+			fmt.Printf("line %d: %s\n", e.Line, e.Message)
+		}
+	}
+	fmt.Printf("%s: rows=%d inserted=%d updated=%d removed=%d errors=%d dry_run=%t applied=%t\n",
+		rep.Kind, rep.Rows, rep.Inserted, rep.Updated, rep.Removed, len(rep.Errors), rep.DryRun, rep.Applied)
+	if !rep.OK() {
+		return 1
+	}
+
+	// 실행 중인 서버들의 사물함 목록 캐시 무효화 (Redis가 없으면 로그만 남고 max age로 갱신됨)
+	if rep.Applied && kind != bulk.KindRoster {
+		rdb := cache.NewRedis()
+		defer rdb.Close()
+		lockercache.New(pool, rdb).Publish(ctx, 0, lockercache.EventAdmin)
+	}
+	return 0
+}
@@ -13,8 +13,6 @@ import (
 	"context"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -28,7 +26,12 @@ import (
 	"github.com/KUCSEPotato/locker-server/internal/api/handlers"
 	"github.com/KUCSEPotato/locker-server/internal/cache"
 	"github.com/KUCSEPotato/locker-server/internal/db"
+	"github.com/KUCSEPotato/locker-server/internal/holdstore"
+	"github.com/KUCSEPotato/locker-server/internal/lifecycle"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/KUCSEPotato/locker-server/internal/reconcile"
 	"github.com/KUCSEPotato/locker-server/internal/scheduler"
+	"github.com/KUCSEPotato/locker-server/internal/util"
 
 	// .env 자동 로딩
 	"github.com/joho/godotenv"
@@ -46,14 +49,36 @@ func main() {
 	// 서버의 표준 시간대(로그 타임스탬프 등)를 서울로 고정
 	_ = os.Setenv("TZ", "Asia/Seoul")
 
-	// 컨텍스트: DB 커넥션 준비/헬스체크 등에 사용
-	ctx := context.Background()
+	// 하위 명령: CSV 일괄 가져오기 (서버는 띄우지 않음)
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+
+	// 생명주기 관리자: 루트 컨텍스트 + 구성 요소 시작/종료 순서
+	// 종료는 등록의 역순 → http(요청 drain) → listener → scheduler → redis-monitor → redis → postgres
+	lc := lifecycle.New()
+	ctx := lc.Context()
 
 	// PostgreSQL 풀 생성 (pgxpool)
 	pool := db.NewPool(ctx)
+	lc.Add(lifecycle.Component{Name: "postgres", Stop: func(context.Context) error {
+		pool.Close()
+		return nil
+	}})
 
 	// Redis 클라이언트 생성 (원자적 hold, 레이트리밋 등에 사용)
 	rdb := cache.NewRedis()
+	lc.Add(lifecycle.Component{Name: "redis", Stop: func(context.Context) error {
+		return rdb.Close()
+	}})
+
+	// Redis 상태 감시: 장애 시 조회 API만 동작하는 degraded mode (hold 계열은 503)
+	redisHealth := cache.NewMonitor(rdb)
+	lc.Add(lifecycle.Component{
+		Name:  "redis-monitor",
+		Start: func(ctx context.Context) error { redisHealth.Start(ctx); return nil },
+		Stop:  func(context.Context) error { redisHealth.Stop(); return nil },
+	})
 
 	// Fiber 앱 생성 + 핵심 타임아웃 설정
 	app := fiber.New(fiber.Config{
@@ -73,26 +98,69 @@ func main() {
 	app.Use(
 		cors.New(cors.Config{
 			AllowOrigins: "https://www.kucisc.kr, https://kucisc.kr, http://localhost:3000",
-			AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-			AllowMethods: "GET, POST, HEAD, PUT, DELETE, PATCH",
+			AllowHeaders: "Origin, Content-Type, Accept, Authorization, X-CSRF-Token, Idempotency-Key",
+			// 재생된 응답인지 프론트에서 구분할 수 있도록 노출
+			ExposeHeaders: "Idempotent-Replayed",
+			AllowMethods:  "GET, POST, HEAD, PUT, DELETE, PATCH",
+			// 쿠키 전송 모드(AUTH_TOKEN_TRANSPORT=cookie)에서 브라우저가 쿠키를 보내려면 필요
+			AllowCredentials: true,
 		}),
 		logger.New(),  // 요청 로그 출력
 		recover.New(), // panic 복구
 	)
 
 	// 의존성 주입용 구조체(핸들러들이 DB/Redis에 접근할 때 사용)
-	deps := handlers.Deps{DB: pool, RDB: rdb}
+	holds := holdstore.New(rdb)
+
+	// 사물함 목록 스냅샷 캐시: 상태 변경 이벤트(locker:events 채널)로 무효화, 모든 인스턴스가 구독
+	lockers := lockercache.New(pool, rdb)
+	lc.Add(lifecycle.Component{
+		Name:  "locker-cache",
+		Start: func(ctx context.Context) error { lockers.Start(ctx); return nil },
+		Stop:  func(context.Context) error { lockers.Stop(); return nil },
+	})
 
-	// Start real-time cleanup scheduler for expired holds (Redis keyspace notifications)
-	scheduler.StartRealtimeCleanup(pool, rdb)
+	reconciler := reconcile.New(pool, rdb, holds, lockers)
+
+	// 백그라운드 작업: 여러 인스턴스 중 Postgres advisory lock을 가진 리더 한 곳에서만 실행 (Redis 장애와 무관)
+	jobs := scheduler.NewRunner(pool)
+	// 만료된 hold 정리 (Postgres hold_expires_at 기준, FOR UPDATE SKIP LOCKED)
+	jobs.Register(scheduler.ExpiryJob(pool, holds, lockers))
+	// Redis hold ↔ DB 배정 ↔ locker_info 정합성 검사 (orphan 키/owner 불일치 탐지 및 복구)
+	jobs.Register(scheduler.ReconcileJob(reconciler))
+	lc.Add(lifecycle.Component{
+		Name:  "scheduler",
+		Start: func(ctx context.Context) error { jobs.Start(ctx); return nil },
+		Stop:  func(context.Context) error { jobs.Stop(); return nil }, // 실행 중인 작업 종료 대기 + 리더 lease 반납
+	})
 
-	// Start background cleanup scheduler as fallback (every 10 seconds)
-	scheduler.StartCleanupScheduler(pool, rdb)
+	// (선택) Redis keyspace 만료 알림으로 hold를 조금 더 빨리 정리 (HOLD_EXPIRY_KEYSPACE_EVENTS=true, 리더만 처리)
+	listener := scheduler.NewExpiryListener(pool, rdb, holds, lockers, jobs)
+	lc.Add(lifecycle.Component{
+		Name:  "keyspace-listener",
+		Start: func(ctx context.Context) error { listener.Start(ctx); return nil },
+		Stop:  func(context.Context) error { listener.Stop(); return nil },
+	})
+
+	// 종료 시 끝까지 처리할 요청(hold/confirm 등) 추적
+	inflight := lifecycle.NewInFlight()
+
+	deps := handlers.Deps{
+		DB:          pool,
+		RDB:         rdb,
+		Holds:       holds,
+		Reconciler:  reconciler,
+		Jobs:        jobs,
+		InFlight:    inflight,
+		RedisHealth: redisHealth,
+		Listener:    listener,
+		Lockers:     lockers,
+	}
 
 	// Redis connection test
 	log.Printf("Testing Redis connection to: %s", os.Getenv("REDIS_ADDR"))
 
-	testctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	testctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	if err := deps.RDB.Ping(testctx).Err(); err != nil {
@@ -107,26 +175,38 @@ func main() {
 	// swagger
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
 
-	// 서버 종료 신호 처리
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-
-	// Fiber 서버를 goroutine으로 실행
-	go func() {
-		if err := app.Listen(os.Getenv("APP_ADDR")); err != nil {
-			log.Printf("Fiber server stopped: %v", err)
-		}
-	}()
-
-	<-c // 종료 신호 대기
-	log.Println("Shutting down server...")
-	_ = app.Shutdown()
-	pool.Close() // PostgreSQL 풀 닫기
-	// Redis 클라이언트 닫기
-	if err := rdb.Close(); err != nil {
-		log.Printf("Error closing Redis client: %v", err)
+	// HTTP 서버: 종료 시 새 hold/confirm 요청은 503으로 막고, 진행 중인 요청을 SHUTDOWN_DRAIN_SEC(기본 10초)까지 기다린 뒤 닫는다.
+	drainTimeout := time.Duration(util.EnvInt("SHUTDOWN_DRAIN_SEC", 10)) * time.Second
+	lc.Add(lifecycle.Component{
+		Name: "http",
+		Start: func(context.Context) error {
+			// Fiber 서버를 goroutine으로 실행
+			go func() {
+				if err := app.Listen(os.Getenv("APP_ADDR")); err != nil {
+					lc.Fail(err)
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			drainCtx, cancel := context.WithTimeout(ctx, drainTimeout)
+			defer cancel()
+			log.Printf("Draining %d in-flight locker requests (deadline %s)", inflight.Count(), drainTimeout)
+			if remaining, err := inflight.Drain(drainCtx); err != nil {
+				log.Printf("Drain deadline exceeded: %d locker requests still in flight", remaining)
+			}
+			return app.ShutdownWithContext(ctx)
+		},
+	})
+
+	if err := lc.Start(); err != nil {
+		log.Fatalf("Server failed to start: %v", err)
 	}
 
+	lc.Wait() // 종료 신호 대기
+	// 전체 종료 제한 시간 SHUTDOWN_TIMEOUT_SEC (기본 20초)
+	lc.Shutdown(time.Duration(util.EnvInt("SHUTDOWN_TIMEOUT_SEC", 20)) * time.Second)
+
 	log.Println("Server gracefully stopped")
 
 	// HTTP 서버 시작 (예: :3000)
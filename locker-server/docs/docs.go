@@ -15,45 +15,132 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login-or-register": {
-            "post": {
-                "description": "학번/이름/전화번호가 일치하면 로그인, 불일치하면 새로 회원가입 후 로그인.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/audit": {
+            "get": {
+                "description": "사물함 상태 변경, 로그인/로그아웃/refresh, 세션 revoke, 프로필 변경, 관리자 작업의 감사 로그를 조회합니다. 행위자, 동작(쉼표 구분, \"locker.\"처럼 점으로 끝나면 접두사), 대상, 기간으로 거를 수 있고 event_id 기준 cursor 페이지네이션합니다. 특정 사물함의 이력은 locker_id=101\u0026order=asc 처럼 조회합니다.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "로그인 또는 자동 회원가입 (통합 인증)",
+                "summary": "감사 로그 조회 (관리자)",
                 "parameters": [
                     {
-                        "description": "로그인/회원가입 정보",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.LoginOrRegisterRequest"
-                        }
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "행위자 serial_id",
+                        "name": "actor_serial_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "admin",
+                            "system"
+                        ],
+                        "type": "string",
+                        "description": "행위자 구분",
+                        "name": "actor_role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "동작 (쉼표 구분, 예: locker.hold,locker.claim 또는 auth.)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "locker",
+                            "user",
+                            "session",
+                            "location",
+                            "import",
+                            "eligibility_exception"
+                        ],
+                        "type": "string",
+                        "description": "대상 종류",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "대상 ID (target_type 필요)",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "target_type=locker\u0026target_id=... 의 줄임",
+                        "name": "locker_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "시작 시각 (RFC3339 또는 YYYY-MM-DD, 포함)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "끝 시각 (RFC3339 또는 YYYY-MM-DD, 미포함)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "event_id 정렬",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "이전 응답의 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "페이지 크기 (최대 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "기존 사용자 로그인 성공",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.LoginOrRegisterResponse"
+                            "$ref": "#/definitions/handlers.ListAuditEventsResponse"
                         }
                     },
-                    "201": {
-                        "description": "새 사용자 회원가입 및 로그인 성공",
+                    "400": {
+                        "description": "invalid filter",
                         "schema": {
-                            "$ref": "#/definitions/handlers.LoginOrRegisterResponse"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "invalid name length",
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -67,51 +154,150 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
-            "post": {
-                "description": "현재 사용 중인 Access Token과 Refresh Token을 모두 무효화합니다.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/audit/export": {
+            "get": {
+                "description": "GET /admin/audit과 같은 필터로 감사 로그를 CSV 또는 XLSX 파일로 내려받습니다. 기본은 시간 순(order=asc)이며 한 번에 최대 100000건입니다. before/after는 JSON 문자열로 들어갑니다. =, +, -, @, 탭, CR로 시작하는 셀(User-Agent 등)은 수식으로 실행되지 않도록 앞에 '가 붙습니다.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "로그아웃",
+                "summary": "감사 로그 내보내기 (관리자)",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
                         "description": "Bearer {access_token}",
                         "name": "Authorization",
-                        "in": "header"
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "description": "로그아웃 정보",
-                        "name": "payload",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.LogoutRequest"
-                        }
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "파일 형식",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "행위자 serial_id",
+                        "name": "actor_serial_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "admin",
+                            "system"
+                        ],
+                        "type": "string",
+                        "description": "행위자 구분",
+                        "name": "actor_role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "동작 (쉼표 구분, 점으로 끝나면 접두사)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "locker",
+                            "user",
+                            "session",
+                            "location",
+                            "import",
+                            "eligibility_exception"
+                        ],
+                        "type": "string",
+                        "description": "대상 종류",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "대상 ID (target_type 필요)",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "target_type=locker\u0026target_id=... 의 줄임",
+                        "name": "locker_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "시작 시각 (RFC3339 또는 YYYY-MM-DD, 포함)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "끝 시각 (RFC3339 또는 YYYY-MM-DD, 미포함)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "event_id 정렬",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "이 event_id 다음부터",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100000,
+                        "description": "최대 건수 (최대 100000)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "감사 로그 파일",
                         "schema": {
-                            "$ref": "#/definitions/handlers.LogoutResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "unknown format / invalid filter",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -119,19 +305,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/me": {
+        "/admin/eligibility/check": {
             "get": {
-                "description": "JWT 토큰을 통해 인증된 현재 사용자의 학번, 이름, 전화번호를 반환합니다.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "학번(과 선택적으로 이름)이 현재 회차의 신청 자격 규칙을 통과하는지, 막힌다면 어떤 사유인지 보여줍니다. 예외로 통과하면 exception=true입니다.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "현재 로그인된 사용자 정보 조회",
+                "summary": "신청 자격 확인 (관리자)",
                 "parameters": [
                     {
                         "type": "string",
@@ -140,23 +323,42 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "학번",
+                        "name": "student_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "이름 (명단 이름과 비교)",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GetMeResponse"
+                            "$ref": "#/definitions/handlers.EligibilityCheckResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid student_id format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "unauthorized - invalid or missing token",
+                        "description": "unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "user not found",
+                    "403": {
+                        "description": "admin only",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -170,90 +372,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/refresh": {
-            "post": {
-                "description": "Refresh 토큰을 사용하여 새로운 Access 토큰을 발급합니다.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/eligibility/exceptions": {
+            "get": {
+                "description": "관리자가 허용한 학생별 신청 자격 예외를 최신순으로 반환합니다. student_id로 거를 수 있습니다.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "토큰 갱신",
+                "summary": "신청 자격 예외 목록 (관리자)",
                 "parameters": [
                     {
-                        "description": "토큰 갱신 요청 정보",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.RefreshRequest"
-                        }
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "학번",
+                        "name": "student_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RefreshResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.EligibilityException"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "admin only",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "서버 상태 확인 (DB, Redis 연결 상태 포함)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "헬스 체크",
-                "responses": {
-                    "200": {
-                        "description": "서버가 정상입니다",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     },
                     "500": {
-                        "description": "서버에 문제가 있습니다",
+                        "description": "internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/lockers": {
-            "get": {
-                "description": "모든 사물함 목록과 사용 가능한 사물함 수를 반환합니다",
+            },
+            "post": {
+                "description": "학생 한 명을 회차(round_id, 생략하면 모든 회차)의 신청 자격 규칙에서 제외합니다. 같은 학생/회차의 예외가 이미 있으면 사유만 바꿉니다.",
                 "consumes": [
                     "application/json"
                 ],
@@ -261,9 +437,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lockers"
+                    "admin"
                 ],
-                "summary": "사물함 목록 조회",
+                "summary": "신청 자격 예외 추가 (관리자)",
                 "parameters": [
                     {
                         "type": "string",
@@ -272,23 +448,44 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "예외 정보",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EligibilityExceptionRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "사물함 목록과 사용 가능 수",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ListLockersResponse"
+                            "$ref": "#/definitions/handlers.EligibilityException"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid student_id format / reason is required / unknown round_id",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "인증 필요",
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "서버 오류",
+                        "description": "internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -296,19 +493,81 @@ const docTemplate = `{
                 }
             }
         },
-        "/lockers/me": {
-            "get": {
-                "description": "현재 로그인한 사용자가 소유한 사물함 정보를 반환합니다. 소유한 사물함이 없으면 locker가 null로 반환됩니다.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/eligibility/exceptions/{id}": {
+            "delete": {
+                "description": "자격 예외를 삭제합니다. 이미 받은 사물함에는 영향이 없고, 이후 로그인/hold부터 규칙이 다시 적용됩니다.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lockers"
+                    "admin"
                 ],
-                "summary": "내 사물함 조회",
+                "summary": "신청 자격 예외 삭제 (관리자)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "exception_id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "삭제됨"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "exception not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/export/assignments": {
+            "get": {
+                "description": "사물함 배정을 사용자 정보(serial_id, 학번, 이름, 전화번호)와 함께 CSV 또는 XLSX 파일로 내려받습니다. state=active(기본, hold+confirmed), confirmed, all 중 하나를 고릅니다.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "배정 내보내기 (관리자)",
                 "parameters": [
                     {
                         "type": "string",
@@ -317,23 +576,58 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "파일 형식",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "confirmed",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "active",
+                        "description": "배정 상태",
+                        "name": "state",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "내 사물함 정보 (없으면 locker가 null)",
+                        "description": "배정 목록 파일",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MyLockerResponse"
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "unknown format / invalid state",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "서버 오류 - 데이터베이스 조회 실패",
+                        "description": "internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -341,19 +635,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/lockers/{id}/confirm": {
+        "/admin/import/{kind}": {
             "post": {
-                "description": "선점한 사물함을 확정합니다 (실제 소유권 획득). hold 상태에서 confirmed 상태로 전환되며, 사물함의 소유자로 등록됩니다.",
+                "description": "위치(locations: name, grid_rows, grid_cols), 사물함(lockers: locker_id, location 또는 location_id, size, row, near_outlet, out_of_service), 신청 자격 명단(roster: student_id, name, department, note, fee_paid)을 CSV로 가져옵니다. 첫 줄은 헤더입니다. 행마다 검증하고 에러를 줄 번호와 함께 보고하며, 에러가 하나라도 있으면 아무것도 반영하지 않습니다. dry_run=true면 DB 반영까지 시험해 보고 롤백합니다. replace=true(명단만)면 파일에 없는 학생을 명단에서 제거합니다. 본문은 CSV 그대로(text/csv) 또는 multipart의 file 필드로 보냅니다.",
                 "consumes": [
-                    "application/json"
+                    "text/csv",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lockers"
+                    "admin"
                 ],
-                "summary": "사물함 확정",
+                "summary": "CSV 일괄 가져오기 (관리자)",
                 "parameters": [
                     {
                         "type": "string",
@@ -364,43 +659,69 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "maximum": 999,
-                        "minimum": 1,
-                        "type": "integer",
-                        "example": 101,
-                        "description": "사물함 ID (선점한 사물함)",
-                        "name": "id",
+                        "enum": [
+                            "locations",
+                            "lockers",
+                            "roster"
+                        ],
+                        "type": "string",
+                        "description": "가져올 대상",
+                        "name": "kind",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true면 검증/시험 반영 후 롤백",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "(roster) 파일에 없는 학생 제거",
+                        "name": "replace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV 파일 (multipart로 보낼 때)",
+                        "name": "file",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "확정 완료",
+                        "description": "반영(또는 dry run) 성공",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SimpleSuccessResponse"
+                            "$ref": "#/definitions/bulk.Report"
                         }
                     },
                     "400": {
-                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "description": "unknown import kind / replace is only supported for roster imports / missing csv",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "description": "unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "선점이 만료되었거나 없음 - hold 상태가 아니거나 5분이 경과함",
+                    "403": {
+                        "description": "admin only",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "422": {
+                        "description": "행 에러 - 아무것도 반영되지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/bulk.Report"
+                        }
+                    },
                     "500": {
-                        "description": "서버 오류 - 데이터베이스 트랜잭션 실패",
+                        "description": "internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -408,9 +729,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/lockers/{id}/hold": {
-            "post": {
-                "description": "특정 사물함을 선점합니다 (1분간 예약). Redis와 DB를 통해 동시성 제어를 하며, 성공 시 사물함 정보를 반환합니다. 신청 기간 외에는 접근이 불가능합니다.",
+        "/admin/locations/{id}/layout": {
+            "put": {
+                "description": "위치의 격자 크기(rows×cols, 최대 50×50)와 사물함별 칸(row/col, 1부터)을 통째로 교체합니다. positions에 없는 이 위치의 사물함은 배치 해제됩니다. 다른 위치의 사물함, 격자 밖 칸, 한 칸에 두 사물함은 400입니다.",
                 "consumes": [
                     "application/json"
                 ],
@@ -418,9 +739,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lockers"
+                    "admin"
                 ],
-                "summary": "사물함 선점",
+                "summary": "위치 배치도 교체 (관리자)",
                 "parameters": [
                     {
                         "type": "string",
@@ -431,49 +752,56 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "maximum": 999,
-                        "minimum": 1,
                         "type": "integer",
-                        "example": 101,
-                        "description": "사물함 ID (1-999 범위)",
+                        "example": 1,
+                        "description": "위치 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "배치도",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateLocationLayoutRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "선점 성공 - 사물함 정보 포함",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.HoldSuccessResponse"
+                            "$ref": "#/definitions/handlers.LocationLayout"
                         }
                     },
                     "400": {
-                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "description": "invalid layout",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "description": "unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "신청 기간 외 - 신청 시작 전이거나 마감 후",
+                        "description": "admin only",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "이미 선점됨 - 다른 사용자가 이미 선점했거나 본인이 이미 선점한 상태",
+                    "404": {
+                        "description": "location not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "503": {
-                        "description": "서비스 일시 불가 - Redis 서버 장애",
+                    "500": {
+                        "description": "internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -481,9 +809,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/lockers/{id}/release": {
-            "post": {
-                "description": "확정된 사물함을 해제합니다 (소유권 포기). confirmed 상태에서 cancelled 상태로 전환되며, 사물함이 다시 사용 가능해집니다.",
+        "/admin/lockers/{id}/attributes": {
+            "patch": {
+                "description": "사물함의 크기(small/medium/large), 층(top/middle/bottom), 콘센트 근처 여부, 사용 중지 여부와 메모를 수정합니다. 보낸 필드만 바뀌고, size/row/service_note에 빈 문자열을 보내면 지워집니다. 사용 중지로 바꿔도 현재 소유자/hold는 그대로 유지되며, 이후 hold/claim만 거절됩니다.",
                 "consumes": [
                     "application/json"
                 ],
@@ -491,9 +819,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lockers"
+                    "admin"
                 ],
-                "summary": "사물함 해제",
+                "summary": "사물함 속성 수정 (관리자)",
                 "parameters": [
                     {
                         "type": "string",
@@ -504,43 +832,56 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "maximum": 999,
-                        "minimum": 1,
                         "type": "integer",
                         "example": 101,
-                        "description": "사물함 ID (소유한 사물함)",
+                        "description": "사물함 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "바꿀 속성",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateLockerAttributesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "해제 완료",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SimpleSuccessResponse"
+                            "$ref": "#/definitions/handlers.AdminLockerAttributesResponse"
                         }
                     },
                     "400": {
-                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "description": "invalid size / row / nothing to update",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "사물함을 찾을 수 없음 - 소유하지 않은 사물함",
+                        "description": "locker not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "서버 오류 - 데이터베이스 트랜잭션 실패",
+                        "description": "internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -548,19 +889,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/lockers/{id}/release-hold": {
-            "post": {
-                "description": "hold 상태인 사물함 예약을 취소합니다. 사물함이 다시 사용 가능해집니다.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/lockers/{id}/history": {
+            "get": {
+                "description": "사물함 한 개의 배정 이력(누가 언제 hold/확정/해제했고 언제 만료됐는지)을 사용자 정보와 함께 최신순으로 반환합니다. 응답의 next_cursor를 cursor로 넘기면 다음 페이지입니다. 요청 단위의 상세 기록(IP 등)은 GET /admin/audit?locker_id= 로 봅니다.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lockers"
+                    "admin"
                 ],
-                "summary": "사물함 hold 해제",
+                "summary": "사물함별 배정 히스토리 (관리자)",
                 "parameters": [
                     {
                         "type": "string",
@@ -571,43 +909,60 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "maximum": 999,
-                        "minimum": 1,
                         "type": "integer",
                         "example": 101,
-                        "description": "사물함 ID (hold 상태의 사물함)",
+                        "description": "사물함 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "이전 응답의 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "페이지 크기 (최대 200)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "hold 해제 완료",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SimpleSuccessResponse"
+                            "$ref": "#/definitions/handlers.AssignmentHistoryResponse"
                         }
                     },
                     "400": {
-                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "description": "invalid cursor / invalid limit",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "사물함을 찾을 수 없음 - hold 상태가 아님",
+                        "description": "locker not found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "서버 오류 - 데이터베이스 트랜잭션 실패",
+                        "description": "internal server error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -615,201 +970,3387 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/GetAllUsers": {
+        "/admin/reconcile": {
             "get": {
-                "description": "users 테이블의 전체 유저를 조회하고, 총 개수도 함께 반환합니다.",
+                "description": "주기적으로 실행되는 정합성 검사(Redis hold 키 ↔ locker_assignments ↔ locker_info)의 마지막 결과를 반환합니다.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admin"
+                ],
+                "summary": "hold/배정 정합성 검사 결과 조회 (관리자)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "모든 유저 조회",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ListUsersResponse"
+                            "$ref": "#/definitions/reconcile.Report"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "no reconcile report yet",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "invalid credentials"
-                }
-            }
-        },
-        "handlers.GetMeResponse": {
-            "type": "object",
-            "properties": {
-                "name": {
-                    "type": "string"
-                },
-                "phone_number": {
-                    "type": "string"
-                },
-                "student_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "handlers.HoldSuccessResponse": {
-            "type": "object",
-            "properties": {
-                "expires_in": {
-                    "type": "string",
-                    "example": "1 minutes"
-                },
-                "locker": {
-                    "$ref": "#/definitions/handlers.LockerResponse"
-                },
+            },
+            "post": {
+                "description": "Redis hold 키, locker_assignments, locker_info 사이의 불변식을 지금 검사합니다. repair=false(기본)면 보고만 하고, true면 orphan 키 삭제/만료 처리/owner 보정 등 안전한 복구를 수행합니다. owner 충돌처럼 판단이 필요한 항목은 항상 보고만 합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "hold/배정 정합성 검사 즉시 실행 (관리자)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "실행 옵션",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RunReconcileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reconcile.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/duplicates": {
+            "get": {
+                "description": "같은 학번을 가진 계정 그룹과, 학번/이름/전화번호 중 두 가지 이상이 한 글자 이내로 비슷한 계정 그룹을 반환합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "중복 계정 후보 리포트 (관리자)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DuplicateReportResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/merge": {
+            "post": {
+                "description": "merged_serial_ids 계정의 사물함 배정 히스토리, 사물함 소유권, 세션을 survivor_serial_id 계정으로 한 트랜잭션에서 옮기고 병합된 계정을 삭제합니다. 병합될 계정 중 선점(hold) 중인 계정이 있으면 409를 반환합니다(선점을 해제하거나 만료된 뒤 다시 시도). dry_run=true면 실제 반영 없이 결과만 미리 봅니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "중복 계정 병합 (관리자)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "병합 요청",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MergeUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MergeUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid merge request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "more than one active locker/hold among merged users / merged user is holding a locker",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/rekey-serials": {
+            "post": {
+                "description": "예전 SHA256 방식으로 만들어진 계정의 serial_id를 HMAC 방식으로 재발급합니다. 참조 테이블은 ON UPDATE CASCADE로 함께 바뀌고, 해당 계정의 세션은 모두 무효화됩니다(토큰 sub가 바뀌므로 재로그인 필요). 활성 hold가 있는 계정은 대상에서 제외하고(holding), 충돌 없는 후보가 없는 계정은 건너뜁니다(skipped). serial_id 순으로 limit개씩 처리하며, 응답의 next_after_serial_id를 after_serial_id로 넘기면 다음 배치를 처리합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "legacy serial_id 재발급 (관리자)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "재발급 옵션",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RekeySerialsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RekeySerialsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin only",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login-or-register": {
+            "post": {
+                "description": "학번/이름/전화번호가 일치하면 로그인, 불일치하면 새로 회원가입 후 로그인.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "로그인 또는 자동 회원가입 (통합 인증)",
+                "parameters": [
+                    {
+                        "description": "로그인/회원가입 정보",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginOrRegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "기존 사용자 로그인 성공",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginOrRegisterResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "새 사용자 회원가입 및 로그인 성공",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginOrRegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid name length",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "not eligible for this round (not_on_roster | roster_name_mismatch | fee_unpaid | student_id_prefix | admission_year): ...",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "현재 사용 중인 Access Token과 Refresh Token을 모두 무효화합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "로그아웃",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "로그아웃 정보",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LogoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout-all": {
+            "post": {
+                "description": "현재 사용자의 모든 Refresh Token을 무효화하고, 각 세션의 Access Token과 현재 Access Token을 블랙리스트에 올립니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "전체 로그아웃 (모든 디바이스)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RevokeSessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "description": "JWT 토큰을 통해 인증된 현재 사용자의 학번, 이름, 전화번호를 반환합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "현재 로그인된 사용자 정보 조회",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GetMeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "현재 전화번호로 본인 확인 후 계정을 익명화합니다. 보유 중인 hold/사물함은 해제되고 모든 세션이 무효화됩니다. 사물함 배정 히스토리는 감사 목적으로 보존됩니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "회원 탈퇴 (익명화)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "본인 확인 정보",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DeleteMeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "missing required field: current_phone_number",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "current_phone_number does not match",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "현재 전화번호로 본인 확인 후 이름/전화번호를 변경합니다. serial_id는 그대로 유지되므로 이후 새 정보로 로그인해도 같은 계정입니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "내 프로필 수정",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "변경할 정보",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateMeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GetMeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "nothing to update / invalid phone_number format / invalid name length",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "current_phone_number does not match",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "another account already uses this student_id, name and phone_number",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me/export": {
+            "get": {
+                "description": "현재 사용자의 계정 정보, 사물함 배정 히스토리, 세션(로그인) 기록 전체를 JSON 파일로 내려줍니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "내 데이터 내보내기",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportMeResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Refresh 토큰을 사용하여 새로운 Access 토큰을 발급합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "토큰 갱신",
+                "parameters": [
+                    {
+                        "description": "토큰 갱신 요청 정보",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RefreshResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "description": "현재 사용자의 활성(만료/회수되지 않은) 세션 목록을 User-Agent 기반 기기 정보와 함께 반환합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "내 세션(디바이스) 목록",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ListSessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/revoke-others": {
+            "post": {
+                "description": "지금 요청을 보낸 세션을 제외한 모든 세션을 무효화하고, 해당 세션들의 Access Token을 블랙리스트에 올립니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "다른 디바이스 모두 로그아웃",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RevokeSessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "description": "지정한 세션의 Refresh Token을 무효화하고, 그 세션으로 발급된 Access Token도 블랙리스트에 올립니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "세션 하나 revoke",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "세션 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RevokeSessionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid session id",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "session not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "프로세스가 요청을 처리할 수 있는지만 확인합니다. DB/Redis 상태와 무관하게 200을 반환합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "생존 확인 (liveness)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LiveResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Postgres/Redis 지연 시간, Redis 만료 알림 리스너 동작 여부, 백그라운드 작업 마지막 실행 상태를 보고합니다. Redis 장애 시 degraded(200, 조회만 가능), Postgres 장애 또는 종료 중이면 not_ready(503)입니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "준비 상태 확인 (readiness)",
+                "responses": {
+                    "200": {
+                        "description": "ready 또는 degraded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReadyResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "not_ready",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReadyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/locations": {
+            "get": {
+                "description": "사물함 위치별로 전체 사물함 수와 상태별(free/held/taken/out_of_service) 수, 격자 배치도(칸별 상태 포함)를 반환합니다. If-None-Match가 현재 ETag와 같으면 304를 반환합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "위치 목록 조회",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "이전 응답의 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "위치별 요약",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ListLocationsResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "변경 없음"
+                    },
+                    "401": {
+                        "description": "인증 필요",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/locations/{id}": {
+            "get": {
+                "description": "위치 하나의 상태별 사물함 수와 격자 배치도를 반환합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "위치 하나 조회",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "이전 응답의 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "위치 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LocationSummary"
+                        }
+                    },
+                    "304": {
+                        "description": "변경 없음"
+                    },
+                    "400": {
+                        "description": "잘못된 위치 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "location not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/locations/{id}/map.svg": {
+            "get": {
+                "description": "위치의 격자 배치도를 SVG 이미지로 반환합니다. 각 칸은 사물함 번호와 현재 상태 색(사용 가능: 초록, 선점 중: 주황, 사용 중: 회색, 사용 중지: 빨강)으로 표시됩니다.",
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "위치 배치도 SVG",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "이전 응답의 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "위치 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SVG 문서",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "변경 없음"
+                    },
+                    "400": {
+                        "description": "잘못된 위치 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "location not found / location has no layout",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockers": {
+            "get": {
+                "description": "사물함 목록(위치, 상태 포함)과 사용 가능한 사물함 수를 반환합니다. 소유자 정보(owner, owner_serial_id)는 본인 사물함이거나 관리자일 때만 포함됩니다. 위치/상태/번호 범위/속성(크기, 층, 콘센트)으로 거르고 속성으로 정렬할 수 있습니다. limit 또는 cursor를 보내면 cursor 페이지네이션하고(next_cursor로 다음 페이지), 둘 다 없으면 조건에 맞는 사물함 전체를 반환합니다(next_cursor는 null). 응답에 ETag가 포함되며, If-None-Match가 현재 ETag와 같으면 본문 없이 304를 반환합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "사물함 목록 조회",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "이전 응답의 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "위치 ID",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "free,held",
+                        "description": "상태 (free, held, taken, out_of_service - 쉼표로 여러 개)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "사물함 번호 하한 (포함)",
+                        "name": "min_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "사물함 번호 상한 (포함)",
+                        "name": "max_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "medium,large",
+                        "description": "크기 (small, medium, large - 쉼표로 여러 개)",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "middle",
+                        "description": "층 (top, middle, bottom - 쉼표로 여러 개)",
+                        "name": "row",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "콘센트 근처만 (true) / 아닌 것만 (false)",
+                        "name": "near_outlet",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "-size",
+                        "description": "정렬 (locker_id, size, -size, row, -row, near_outlet). 속성이 없는 사물함은 항상 뒤로",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "페이지 크기 (최대 500). 생략 시 전체 반환, cursor만 보내면 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "이전 응답의 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "사물함 목록과 사용 가능 수",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ListLockersResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "변경 없음"
+                    },
+                    "400": {
+                        "description": "잘못된 필터/페이지 파라미터",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockers/me": {
+            "get": {
+                "description": "현재 로그인한 사용자가 소유한 사물함 정보를 반환합니다. 소유한 사물함이 없으면 locker가 null로 반환됩니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "내 사물함 조회",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "내 사물함 정보 (없으면 locker가 null)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MyLockerResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류 - 데이터베이스 조회 실패",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockers/me/history": {
+            "get": {
+                "description": "현재 사용자의 사물함 배정 이력(hold, 만료, 확정, 해제)을 위치 이름, 시각과 함께 최신순으로 반환합니다. 각 배정의 events는 시간 순 타임라인입니다. 응답의 next_cursor를 cursor로 넘기면 다음 페이지입니다.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "내 사물함 배정 히스토리",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "이전 응답의 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "페이지 크기 (최대 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AssignmentHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid cursor / invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockers/{id}/claim": {
+            "post": {
+                "description": "hold 단계 없이 한 번의 요청으로 사물함을 확정합니다. 현재 회차에 바로 확정(direct confirm)이 켜져 있을 때만 사용할 수 있으며, 신청 기간 외에는 접근이 불가능합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "사물함 바로 확정",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "maximum": 999,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 101,
+                        "description": "사물함 ID (1-999 범위)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "확정 완료",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ClaimSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "신청 기간 외 (code=not_open_yet | closed). 현재 회차에서 바로 확정이 꺼져 있거나 회차 신청 자격이 없으면 ErrorResponse",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ApplicationWindowResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "locker not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "이미 다른 사용자가 선점/소유 중이거나 본인이 이미 활성 사물함을 보유 중, 또는 사용 중지된 사물함",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "같은 Idempotency-Key를 다른 요청에 재사용",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류 - 데이터베이스 트랜잭션 실패",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "서비스 일시 불가 - Redis 서버 장애",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockers/{id}/confirm": {
+            "post": {
+                "description": "선점한 사물함을 확정합니다 (실제 소유권 획득). hold 상태에서 confirmed 상태로 전환되며, 사물함의 소유자로 등록됩니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "사물함 확정",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "maximum": 999,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 101,
+                        "description": "사물함 ID (선점한 사물함)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "확정 완료",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "선점이 만료되었거나 없음 - hold 상태가 아니거나 hold TTL(회차 설정, 기본 1분)이 경과함",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "같은 Idempotency-Key를 다른 요청에 재사용",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류 - 데이터베이스 트랜잭션 실패",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockers/{id}/hold": {
+            "post": {
+                "description": "특정 사물함을 선점합니다 (현재 회차의 hold TTL 동안 예약, 기본 1분). Redis와 DB를 통해 동시성 제어를 하며, 성공 시 사물함 정보를 반환합니다. 신청 기간 외에는 접근이 불가능합니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "사물함 선점",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "maximum": 999,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 101,
+                        "description": "사물함 ID (1-999 범위)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "선점 성공 - 사물함 정보 포함",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HoldSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "신청 기간 외 - 신청 시작 전(code=not_open_yet, 우선 순위 그룹이면 그룹 시작 시각)이거나 마감 후(code=closed). 회차 신청 자격이 없으면 ErrorResponse (not eligible for this round)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ApplicationWindowResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "이미 선점됨 - 다른 사용자가 이미 선점했거나 본인이 이미 선점한 상태, 또는 본인이 다른 사물함을 선점 중, 또는 사용 중지된 사물함",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "같은 Idempotency-Key를 다른 요청에 재사용",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "서비스 일시 불가 - Redis 서버 장애",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockers/{id}/hold/extend": {
+            "post": {
+                "description": "선점(hold) 중인 사물함의 만료 시각을 지금부터 hold TTL 만큼 연장합니다. 연장 가능 횟수는 회차 설정(기본 1회)으로 제한됩니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "사물함 선점 연장",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "maximum": 999,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 101,
+                        "description": "사물함 ID (선점한 사물함)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "연장 완료 - 새 만료 시각 포함",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HoldExtendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "신청 기간 외 (code=not_open_yet | closed)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ApplicationWindowResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "선점이 만료되었거나 없음 / 연장 횟수 초과",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "같은 Idempotency-Key를 다른 요청에 재사용",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "서비스 일시 불가 - Redis 서버 장애",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockers/{id}/release": {
+            "post": {
+                "description": "확정된 사물함을 해제합니다 (소유권 포기). confirmed 상태에서 cancelled 상태로 전환되며, 사물함이 다시 사용 가능해집니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "사물함 해제",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "maximum": 999,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 101,
+                        "description": "사물함 ID (소유한 사물함)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "해제 완료",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "사물함을 찾을 수 없음 - 소유하지 않은 사물함",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "같은 Idempotency-Key를 다른 요청에 재사용",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류 - 데이터베이스 트랜잭션 실패",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockers/{id}/release-hold": {
+            "post": {
+                "description": "hold 상태인 사물함 예약을 취소합니다. 사물함이 다시 사용 가능해집니다. 예약 기록은 지우지 않고 cancelled 상태로 남습니다.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockers"
+                ],
+                "summary": "사물함 hold 해제",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "maximum": 999,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 101,
+                        "description": "사물함 ID (hold 상태의 사물함)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "hold 해제 완료",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "잘못된 요청 - 유효하지 않은 사물함 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "사물함을 찾을 수 없음 - hold 상태가 아님",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "같은 Idempotency-Key를 다른 요청에 재사용",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류 - 데이터베이스 트랜잭션 실패",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rounds/current/clock": {
+            "get": {
+                "description": "서버 시각(마이크로초 정밀도)과 현재 회차의 신청 시작/마감, 호출자의 신청 시작 시각을 반환합니다. t0(요청 직전 기기 시각, Unix ms)을 보내면 그대로 돌려주므로, 응답을 받은 시각 t3과 함께 offset = ((t1 - t0) + (t2 - t3)) / 2 로 기기 시계 오차를 추정할 수 있습니다. 여러 번 재서 delay가 가장 작은 값을 쓰는 것을 권장합니다.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rounds"
+                ],
+                "summary": "서버 시각 + 신청 시작 카운트다운",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "요청 직전 기기 시각 (Unix epoch 밀리초, 소수 허용)",
+                        "name": "t0",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RoundClockResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid t0",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rounds/current/me": {
+            "get": {
+                "description": "현재 회차에서 호출자의 신청 자격, 적용되는 우선 순위 그룹, 신청 시작/마감 시각을 반환합니다. 우선 순위 그룹이 있으면 조건을 만족하는 그룹 중 가장 이른 시작 시각이 적용됩니다.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rounds"
+                ],
+                "summary": "내 신청 일정 조회",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer {access_token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MyRoundScheduleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "인증 필요 - JWT 토큰이 없거나 유효하지 않음",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "서버 오류",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "bulk.Kind": {
+            "type": "string",
+            "enum": [
+                "locations",
+                "lockers",
+                "roster"
+            ],
+            "x-enum-varnames": [
+                "KindLocations",
+                "KindLockers",
+                "KindRoster"
+            ]
+        },
+        "bulk.Report": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "실제로 커밋되었는지",
+                    "type": "boolean"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bulk.RowError"
+                    }
+                },
+                "inserted": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "kind": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/bulk.Kind"
+                        }
+                    ],
+                    "example": "lockers"
+                },
+                "removed": {
+                    "description": "replace로 제거된 명단 행",
+                    "type": "integer",
+                    "example": 0
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 20
+                }
+            }
+        },
+        "bulk.RowError": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string",
+                    "example": "locker_id"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "message": {
+                    "type": "string",
+                    "example": "duplicate locker_id 101 (also on line 2)"
+                }
+            }
+        },
+        "eligibility.Decision": {
+            "type": "object",
+            "properties": {
+                "eligible": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "exception": {
+                    "description": "관리자 예외로 통과",
+                    "type": "boolean",
+                    "example": false
+                },
+                "message": {
+                    "description": "사용자에게 보여줄 설명",
+                    "type": "string",
+                    "example": "student ID is not on the eligible roster for this round"
+                },
+                "reason": {
+                    "description": "불합격 사유 코드",
+                    "type": "string",
+                    "example": "not_on_roster"
+                }
+            }
+        },
+        "handlers.AdminLockerAttributesResponse": {
+            "type": "object",
+            "properties": {
+                "locker_id": {
+                    "type": "integer",
+                    "example": 101
+                },
+                "near_outlet": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "out_of_service": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "row": {
+                    "type": "string",
+                    "enum": [
+                        "top",
+                        "middle",
+                        "bottom"
+                    ],
+                    "example": "middle"
+                },
+                "service_note": {
+                    "type": "string",
+                    "example": "문 잠금장치 고장"
+                },
+                "size": {
+                    "type": "string",
+                    "enum": [
+                        "small",
+                        "medium",
+                        "large"
+                    ],
+                    "example": "medium"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "integer",
+                    "example": 123456789012
+                }
+            }
+        },
+        "handlers.ApplicationWindowResponse": {
+            "type": "object",
+            "properties": {
+                "closes_at": {
+                    "description": "신청 마감 (null이면 제한 없음)",
+                    "type": "string",
+                    "example": "2025-03-06T18:00:00+09:00"
+                },
+                "code": {
+                    "type": "string",
+                    "enum": [
+                        "not_open_yet",
+                        "closed"
+                    ],
+                    "example": "not_open_yet"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "아직 신청 기간이 아닙니다. 신청 시작: 2025-03-02 10:00:00"
+                },
+                "group": {
+                    "description": "적용된 우선 순위 그룹",
+                    "type": "string",
+                    "example": "4학년"
+                },
+                "opens_at": {
+                    "description": "호출자의 신청 시작 (우선 순위 그룹 반영, null이면 제한 없음)",
+                    "type": "string",
+                    "example": "2025-03-02T10:00:00+09:00"
+                },
+                "server_time": {
+                    "type": "string",
+                    "example": "2025-03-02T09:59:58.512+09:00"
+                }
+            }
+        },
+        "handlers.AssignmentEvent": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "hold",
+                        "claim",
+                        "confirm",
+                        "expire",
+                        "hold_release",
+                        "release"
+                    ],
+                    "example": "hold"
+                }
+            }
+        },
+        "handlers.AssignmentHistoryItem": {
+            "type": "object",
+            "properties": {
+                "assignment_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AssignmentEvent"
+                    }
+                },
+                "hold_expires_at": {
+                    "type": "string"
+                },
+                "location_name": {
+                    "type": "string",
+                    "example": "정보관 B1 엘리베이터"
+                },
+                "locker_id": {
+                    "type": "integer",
+                    "example": 101
+                },
+                "released_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "hold",
+                        "confirmed",
+                        "cancelled",
+                        "expired"
+                    ],
+                    "example": "confirmed"
+                },
+                "user": {
+                    "description": "관리자 사물함별 조회에서만 (탈퇴/병합으로 없어진 계정이면 null)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.AssignmentHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "assignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AssignmentHistoryItem"
+                    }
+                },
+                "next_cursor": {
+                    "description": "다음 페이지 cursor (마지막 페이지면 null)",
+                    "type": "string",
+                    "example": "120"
+                }
+            }
+        },
+        "handlers.AuditEventResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "locker.hold"
+                },
+                "actor_role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "admin",
+                        "system"
+                    ],
+                    "example": "user"
+                },
+                "actor_serial_id": {
+                    "description": "null이면 시스템/익명",
+                    "type": "integer",
+                    "example": 123456789012
+                },
+                "after": {
+                    "description": "변경 후 상태 (없으면 null)",
+                    "type": "object"
+                },
+                "before": {
+                    "description": "변경 전 상태 (없으면 null)",
+                    "type": "object"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string",
+                    "example": "101"
+                },
+                "target_type": {
+                    "type": "string",
+                    "example": "locker"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
+                }
+            }
+        },
+        "handlers.ClaimSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "confirmed_at": {
+                    "type": "string",
+                    "example": "2025-10-15T15:00:03+09:00"
+                },
+                "locker_id": {
+                    "type": "integer",
+                    "example": 101
+                },
+                "message": {
+                    "type": "string",
+                    "example": "locker claimed successfully"
+                }
+            }
+        },
+        "handlers.DeleteMeRequest": {
+            "type": "object",
+            "properties": {
+                "current_phone_number": {
+                    "type": "string",
+                    "example": "01012345678"
+                }
+            }
+        },
+        "handlers.DependencyCheck": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "timeout"
+                },
+                "latency_ms": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "handlers.DuplicateGroup": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "2025320000"
+                },
+                "reason": {
+                    "description": "same_student_id | similar_identity",
+                    "type": "string",
+                    "example": "same_student_id"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DuplicateUser"
+                    }
+                }
+            }
+        },
+        "handlers.DuplicateReportResponse": {
+            "type": "object",
+            "properties": {
+                "by_similarity": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DuplicateGroup"
+                    }
+                },
+                "by_student_id": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DuplicateGroup"
+                    }
+                }
+            }
+        },
+        "handlers.DuplicateUser": {
+            "type": "object",
+            "properties": {
+                "active_locker_id": {
+                    "description": "현재 소유 중인 사물함",
+                    "type": "integer",
+                    "example": 101
+                },
+                "assignments": {
+                    "description": "전체 배정 히스토리 수",
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "홍길동"
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "01012345678"
+                },
+                "serial_id": {
+                    "type": "integer",
+                    "example": 1234567890
+                },
+                "sessions": {
+                    "description": "활성 세션 수",
+                    "type": "integer",
+                    "example": 1
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "2025320000"
+                }
+            }
+        },
+        "handlers.EligibilityCheckResponse": {
+            "type": "object",
+            "properties": {
+                "eligible": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "exception": {
+                    "description": "관리자 예외로 통과",
+                    "type": "boolean",
+                    "example": false
+                },
+                "message": {
+                    "description": "사용자에게 보여줄 설명",
+                    "type": "string",
+                    "example": "student ID is not on the eligible roster for this round"
+                },
+                "reason": {
+                    "description": "불합격 사유 코드",
+                    "type": "string",
+                    "example": "not_on_roster"
+                },
+                "round_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "2025320000"
+                }
+            }
+        },
+        "handlers.EligibilityException": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "exception_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "granted_by": {
+                    "type": "integer",
+                    "example": 123456789012
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "편입생 (명단 누락)"
+                },
+                "round_id": {
+                    "description": "null이면 모든 회차",
+                    "type": "integer",
+                    "example": 3
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "2025320000"
+                }
+            }
+        },
+        "handlers.EligibilityExceptionRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "편입생 (명단 누락)"
+                },
+                "round_id": {
+                    "description": "생략하면 모든 회차",
+                    "type": "integer",
+                    "example": 3
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "2025320000"
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid credentials"
+                }
+            }
+        },
+        "handlers.ExportAssignment": {
+            "type": "object",
+            "properties": {
+                "assignment_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hold_expires_at": {
+                    "type": "string"
+                },
+                "location_name": {
+                    "type": "string",
+                    "example": "정보관 B1 엘리베이터"
+                },
+                "locker_id": {
+                    "type": "integer",
+                    "example": 101
+                },
+                "released_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "confirmed"
+                }
+            }
+        },
+        "handlers.ExportMeResponse": {
+            "type": "object",
+            "properties": {
+                "assignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ExportAssignment"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ExportSession"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/handlers.ExportUser"
+                }
+            }
+        },
+        "handlers.ExportSession": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.10"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExportUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "홍길동"
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "01012345678"
+                },
+                "serial_id": {
+                    "type": "integer",
+                    "example": 123456789012
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "2025320000"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.GetMeResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.HoldExtendResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-10-15T15:02:00+09:00"
+                },
+                "extensions_left": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "locker_id": {
+                    "type": "integer",
+                    "example": 101
+                },
+                "message": {
+                    "type": "string",
+                    "example": "hold extended successfully"
+                }
+            }
+        },
+        "handlers.HoldSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-10-15T15:01:00+09:00"
+                },
+                "locker": {
+                    "$ref": "#/definitions/handlers.LockerResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "locker held successfully"
+                }
+            }
+        },
+        "handlers.LayoutCell": {
+            "type": "object",
+            "properties": {
+                "col": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "locker_id": {
+                    "type": "integer",
+                    "example": 101
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "free",
+                        "held",
+                        "taken",
+                        "out_of_service"
+                    ],
+                    "example": "free"
+                }
+            }
+        },
+        "handlers.LayoutPosition": {
+            "type": "object",
+            "properties": {
+                "col": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "locker_id": {
+                    "type": "integer",
+                    "example": 101
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handlers.ListAuditEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AuditEventResponse"
+                    }
+                },
+                "next_cursor": {
+                    "description": "다음 페이지 cursor (마지막 페이지면 null)",
+                    "type": "string",
+                    "example": "1000"
+                }
+            }
+        },
+        "handlers.ListLocationsResponse": {
+            "type": "object",
+            "properties": {
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LocationSummary"
+                    }
+                }
+            }
+        },
+        "handlers.ListLockersResponse": {
+            "type": "object",
+            "properties": {
+                "available_count": {
+                    "description": "필터와 무관한 전체 free 사물함 수",
+                    "type": "integer",
+                    "example": 45
+                },
+                "lockers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LockerResponse"
+                    }
+                },
+                "next_cursor": {
+                    "description": "다음 페이지 cursor (마지막 페이지이거나 limit/cursor 없이 전체 조회면 null)",
+                    "type": "string",
+                    "example": "120"
+                }
+            }
+        },
+        "handlers.ListSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SessionResponse"
+                    }
+                }
+            }
+        },
+        "handlers.LiveResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "alive"
+                }
+            }
+        },
+        "handlers.LocationLayout": {
+            "type": "object",
+            "properties": {
+                "cells": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LayoutCell"
+                    }
+                },
+                "cols": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "unplaced_locker_ids": {
+                    "description": "이 위치에 있지만 칸이 지정되지 않은 사물함",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.LocationSummary": {
+            "type": "object",
+            "properties": {
+                "free": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "held": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "layout": {
+                    "description": "배치도가 없으면 null",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.LocationLayout"
+                        }
+                    ]
+                },
+                "location_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "정보관 B1"
+                },
+                "out_of_service": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "taken": {
+                    "type": "integer",
+                    "example": 45
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 60
+                }
+            }
+        },
+        "handlers.LockerAttributes": {
+            "type": "object",
+            "properties": {
+                "near_outlet": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "out_of_service": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "row": {
+                    "type": "string",
+                    "enum": [
+                        "top",
+                        "middle",
+                        "bottom"
+                    ],
+                    "example": "middle"
+                },
+                "size": {
+                    "type": "string",
+                    "enum": [
+                        "small",
+                        "medium",
+                        "large"
+                    ],
+                    "example": "medium"
+                }
+            }
+        },
+        "handlers.LockerResponse": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "목록 조회에서만 포함",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.LockerAttributes"
+                        }
+                    ]
+                },
+                "location_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "location_name": {
+                    "type": "string",
+                    "example": "정보관 B1"
+                },
+                "locker_id": {
+                    "type": "integer",
+                    "example": 101
+                },
+                "owner": {
+                    "description": "소유자 본인/관리자에게만 공개",
+                    "type": "string"
+                },
+                "owner_serial_id": {
+                    "description": "소유자 본인/관리자에게만 공개",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "free: 비어 있음, held: 누군가 선점 중, taken: 확정됨, out_of_service: 사용 중지",
+                    "type": "string",
+                    "enum": [
+                        "free",
+                        "held",
+                        "taken",
+                        "out_of_service"
+                    ],
+                    "example": "taken"
+                }
+            }
+        },
+        "handlers.LoginOrRegisterRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.LoginOrRegisterResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "description": "쿠키 모드에서는 HttpOnly 쿠키로만 전달",
+                    "type": "string"
+                },
+                "serial_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "선택적: 요청 body에서 받기",
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "description": "반납할 refresh token",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.LogoutResponse": {
+            "type": "object",
+            "properties": {
                 "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.MergeUsersRequest": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "merged_serial_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "survivor_serial_id": {
+                    "type": "integer",
+                    "example": 123456789012
+                }
+            }
+        },
+        "handlers.MergeUsersResponse": {
+            "type": "object",
+            "properties": {
+                "deleted_users": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "merged_serial_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "moved_assignments": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "moved_lockers": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "moved_sessions": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "survivor_serial_id": {
+                    "type": "integer",
+                    "example": 123456789012
+                }
+            }
+        },
+        "handlers.MyLockerResponse": {
+            "type": "object",
+            "properties": {
+                "locker": {
+                    "$ref": "#/definitions/handlers.LockerResponse"
+                }
+            }
+        },
+        "handlers.MyRoundScheduleResponse": {
+            "type": "object",
+            "properties": {
+                "closes_at": {
+                    "description": "신청 마감 (null이면 제한 없음)",
+                    "type": "string"
+                },
+                "eligible": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "general_opens_at": {
+                    "description": "그룹이 없는 학생의 신청 시작",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "적용된 우선 순위 그룹 (없으면 일반)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.PriorityGroupSummary"
+                        }
+                    ]
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PriorityGroupSummary"
+                    }
+                },
+                "ineligible": {
+                    "description": "자격이 없을 때 사유",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/eligibility.Decision"
+                        }
+                    ]
+                },
+                "open": {
+                    "description": "지금 hold할 수 있는지 (자격 + 기간)",
+                    "type": "boolean",
+                    "example": false
+                },
+                "opens_at": {
+                    "description": "호출자의 신청 시작 (null이면 제한 없음)",
+                    "type": "string"
+                },
+                "round_id": {
+                    "description": "0이면 환경변수 기본 회차",
+                    "type": "integer",
+                    "example": 3
+                },
+                "round_name": {
                     "type": "string",
-                    "example": "locker held successfully"
+                    "example": "2025-1 정기 신청"
+                },
+                "server_time": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.ListLockersResponse": {
+        "handlers.PriorityGroupSummary": {
             "type": "object",
             "properties": {
-                "available_count": {
+                "group_id": {
                     "type": "integer",
-                    "example": 45
+                    "example": 1
                 },
-                "lockers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handlers.LockerResponse"
-                    }
+                "member": {
+                    "description": "호출자가 이 그룹 조건을 만족하는지",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "4학년"
+                },
+                "opens_at": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.ListUsersResponse": {
+        "handlers.ReadyChecks": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer",
-                    "example": 42
+                "expiry_listener": {
+                    "$ref": "#/definitions/handlers.DependencyCheck"
                 },
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handlers.User"
-                    }
+                "postgres": {
+                    "$ref": "#/definitions/handlers.DependencyCheck"
+                },
+                "redis": {
+                    "$ref": "#/definitions/handlers.DependencyCheck"
+                },
+                "scheduler": {
+                    "$ref": "#/definitions/handlers.SchedulerCheck"
                 }
             }
         },
-        "handlers.LockerResponse": {
+        "handlers.ReadyResponse": {
             "type": "object",
             "properties": {
-                "location_id": {
+                "checked_at": {
                     "type": "string"
                 },
-                "locker_id": {
-                    "type": "integer"
+                "checks": {
+                    "$ref": "#/definitions/handlers.ReadyChecks"
                 },
-                "owner": {
-                    "description": "null 가능",
-                    "type": "string"
+                "draining": {
+                    "type": "boolean"
                 },
-                "owner_serial_id": {
-                    "type": "integer"
+                "status": {
+                    "type": "string",
+                    "example": "ready"
                 }
             }
         },
-        "handlers.LoginOrRegisterRequest": {
+        "handlers.RefreshRequest": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string"
-                },
-                "phone_number": {
+                "access_token": {
+                    "description": "선택적: 블랙리스트용",
                     "type": "string"
                 },
-                "student_id": {
+                "refresh_token": {
+                    "description": "쿠키 모드에서는 생략 (refresh_token 쿠키 사용)",
                     "type": "string"
                 }
             }
         },
-        "handlers.LoginOrRegisterResponse": {
+        "handlers.RefreshResponse": {
             "type": "object",
             "properties": {
                 "access_token": {
                     "type": "string"
                 },
                 "refresh_token": {
+                    "description": "쿠키 모드에서는 HttpOnly 쿠키로만 전달",
                     "type": "string"
+                }
+            }
+        },
+        "handlers.RekeySerialsRequest": {
+            "type": "object",
+            "properties": {
+                "after_serial_id": {
+                    "description": "이 serial_id 다음부터 (이전 응답의 next_after_serial_id, 0이면 처음부터)",
+                    "type": "integer",
+                    "example": 123456789012
                 },
-                "serial_id": {
+                "dry_run": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "limit": {
+                    "description": "한 번에 처리할 최대 계정 수 (0이면 100)",
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "handlers.RekeySerialsResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "holding": {
+                    "description": "remaining 중 활성 hold가 있어 대상에서 빠진 계정 수 (hold가 끝난 뒤 처음부터 다시 실행)",
+                    "type": "integer"
+                },
+                "next_after_serial_id": {
+                    "description": "다음 호출의 after_serial_id (이번 배치가 마지막이면 null)",
+                    "type": "integer",
+                    "example": 123456789012
+                },
+                "rekeyed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RekeyedSerial"
+                    }
+                },
+                "remaining": {
+                    "description": "아직 legacy 스킴인 계정 수 (이번 처리 후)",
                     "type": "integer"
+                },
+                "skipped": {
+                    "description": "충돌 없는 후보가 없어 건너뛴 계정",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "handlers.LogoutRequest": {
+        "handlers.RekeyedSerial": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "description": "선택적: 요청 body에서 받기",
-                    "type": "string"
+                "new_serial_id": {
+                    "type": "integer",
+                    "example": 987654321098
                 },
-                "refresh_token": {
-                    "description": "반납할 refresh token",
-                    "type": "string"
+                "old_serial_id": {
+                    "type": "integer",
+                    "example": 123456789012
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "2025320000"
                 }
             }
         },
-        "handlers.LogoutResponse": {
+        "handlers.RevokeSessionsResponse": {
             "type": "object",
             "properties": {
                 "message": {
+                    "type": "string",
+                    "example": "sessions revoked successfully"
+                },
+                "revoked": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.RoundClockResponse": {
+            "type": "object",
+            "properties": {
+                "closes_at": {
+                    "description": "신청 마감",
+                    "type": "string"
+                },
+                "general_opens_at": {
+                    "description": "그룹이 없는 학생의 신청 시작",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "적용된 우선 순위 그룹",
+                    "type": "string",
+                    "example": "4학년"
+                },
+                "opens_at": {
+                    "description": "호출자의 신청 시작 (우선 순위 그룹 반영)",
+                    "type": "string"
+                },
+                "round_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "server_time": {
+                    "description": "t2와 같은 시각 (RFC3339, 나노초)",
                     "type": "string"
+                },
+                "t0": {
+                    "description": "요청의 t0 그대로 (보내지 않았으면 생략)",
+                    "type": "number",
+                    "example": 1740877198001.5
+                },
+                "t1": {
+                    "description": "서버가 요청을 받은 시각",
+                    "type": "number",
+                    "example": 1740877198021.25
+                },
+                "t2": {
+                    "description": "서버가 응답을 보낸 시각",
+                    "type": "number",
+                    "example": 1740877198023.75
                 }
             }
         },
-        "handlers.MyLockerResponse": {
+        "handlers.RunReconcileRequest": {
             "type": "object",
             "properties": {
-                "locker": {
-                    "$ref": "#/definitions/handlers.LockerResponse"
+                "repair": {
+                    "description": "true면 안전하게 고칠 수 있는 항목은 바로 고침",
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "handlers.RefreshRequest": {
+        "handlers.SchedulerCheck": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "description": "선택적: 블랙리스트용",
-                    "type": "string"
+                "instance_id": {
+                    "type": "string",
+                    "example": "locker-api-1:4821:9f3a"
                 },
-                "refresh_token": {
-                    "type": "string"
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scheduler.JobStatus"
+                    }
+                },
+                "leader": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
                 }
             }
         },
-        "handlers.RefreshResponse": {
+        "handlers.SessionResponse": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "current": {
+                    "description": "지금 요청을 보낸 세션인지",
+                    "type": "boolean",
+                    "example": true
+                },
+                "device": {
+                    "$ref": "#/definitions/util.DeviceInfo"
+                },
+                "expires_at": {
                     "type": "string"
                 },
-                "refresh_token": {
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.10"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
                     "type": "string"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) ..."
                 }
             }
         },
@@ -822,6 +4363,67 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.UpdateLocationLayoutRequest": {
+            "type": "object",
+            "properties": {
+                "cols": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LayoutPosition"
+                    }
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "handlers.UpdateLockerAttributesRequest": {
+            "type": "object",
+            "properties": {
+                "near_outlet": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "out_of_service": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "row": {
+                    "type": "string",
+                    "example": "bottom"
+                },
+                "service_note": {
+                    "type": "string",
+                    "example": "문 잠금장치 고장"
+                },
+                "size": {
+                    "type": "string",
+                    "example": "large"
+                }
+            }
+        },
+        "handlers.UpdateMeRequest": {
+            "type": "object",
+            "properties": {
+                "current_phone_number": {
+                    "type": "string",
+                    "example": "01012345678"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "홍길동"
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "01098765432"
+                }
+            }
+        },
         "handlers.User": {
             "description": "users 테이블의 한 레코드(민감정보 제외)",
             "type": "object",
@@ -843,6 +4445,117 @@ const docTemplate = `{
                     "example": "2025320000"
                 }
             }
+        },
+        "reconcile.Finding": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "redis hold without db hold row"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "orphan_redis_hold"
+                },
+                "locker_id": {
+                    "type": "integer",
+                    "example": 101
+                },
+                "repaired": {
+                    "type": "boolean"
+                },
+                "serial_id": {
+                    "type": "integer",
+                    "example": 123456789012
+                }
+            }
+        },
+        "reconcile.Report": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "findings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reconcile.Finding"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "repair": {
+                    "type": "boolean"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "scheduler.JobStatus": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "interval_sec": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "last_duration_ms": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_finished_at": {
+                    "type": "string"
+                },
+                "last_started_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "expired-hold-cleanup"
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "runs": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "util.DeviceInfo": {
+            "type": "object",
+            "properties": {
+                "browser": {
+                    "type": "string",
+                    "example": "Chrome"
+                },
+                "device": {
+                    "description": "mobile | tablet | desktop | unknown",
+                    "type": "string",
+                    "example": "mobile"
+                },
+                "os": {
+                    "type": "string",
+                    "example": "Android"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/bulk"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 감사 로그 조회 / 내보내기 (관리자)
+// - 기록은 internal/audit, 테이블은 017_audit_events.sql 참고
+// - "누가 먼저 잡았나" 분쟁: locker_id=101&order=asc 로 그 사물함의 hold/claim/해제 순서를 본다.
+
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 500
+	maxAuditExportRows   = 100000
+)
+
+// AuditEventResponse 감사 이벤트 한 건
+type AuditEventResponse struct {
+	EventID       int64           `json:"event_id" example:"1024"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	ActorSerialID *int64          `json:"actor_serial_id" example:"123456789012"` // null이면 시스템/익명
+	ActorRole     string          `json:"actor_role" enums:"user,admin,system" example:"user"`
+	Action        string          `json:"action" example:"locker.hold"`
+	TargetType    string          `json:"target_type" example:"locker"`
+	TargetID      *string         `json:"target_id" example:"101"`
+	Before        json.RawMessage `json:"before" swaggertype:"object"` // 변경 전 상태 (없으면 null)
+	After         json.RawMessage `json:"after" swaggertype:"object"`  // 변경 후 상태 (없으면 null)
+	IP            *string         `json:"ip" example:"203.0.113.7"`
+	UserAgent     *string         `json:"user_agent" example:"Mozilla/5.0"`
+}
+
+// ListAuditEventsResponse 감사 이벤트 목록
+type ListAuditEventsResponse struct {
+	Events     []AuditEventResponse `json:"events"`
+	NextCursor *string              `json:"next_cursor" example:"1000"` // 다음 페이지 cursor (마지막 페이지면 null)
+}
+
+// auditFilter 감사 로그 쿼리 파라미터
+type auditFilter struct {
+	actorSerialID *int64
+	actorRole     string
+	actions       []string // 비어 있으면 전체, "locker."처럼 점으로 끝나면 접두사
+	targetType    string
+	targetID      string
+	from, to      *time.Time
+	asc           bool
+	cursor        *int64 // 이 event_id 다음부터 (정렬 방향 기준)
+	limit         int
+}
+
+func parseAuditFilter(c *fiber.Ctx, defaultOrder string, defaultLimit, maxLimit int) (auditFilter, error) {
+	f := auditFilter{limit: defaultLimit}
+
+	if raw := c.Query("actor_serial_id"); raw != "" {
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v <= 0 {
+			return f, fiber.NewError(fiber.StatusBadRequest, "invalid actor_serial_id")
+		}
+		f.actorSerialID = &v
+	}
+	switch f.actorRole = c.Query("actor_role"); f.actorRole {
+	case "", audit.RoleUser, audit.RoleAdmin, audit.RoleSystem:
+	default:
+		return f, fiber.NewError(fiber.StatusBadRequest, "invalid actor_role: must be user, admin or system")
+	}
+	for _, a := range strings.Split(c.Query("action"), ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			f.actions = append(f.actions, a)
+		}
+	}
+	f.targetType = strings.TrimSpace(c.Query("target_type"))
+	f.targetID = strings.TrimSpace(c.Query("target_id"))
+
+	// locker_id: target_type=locker&target_id=... 의 줄임
+	if raw := c.Query("locker_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id < 1 {
+			return f, fiber.NewError(fiber.StatusBadRequest, "invalid locker_id")
+		}
+		if (f.targetType != "" && f.targetType != audit.TargetLocker) || (f.targetID != "" && f.targetID != raw) {
+			return f, fiber.NewError(fiber.StatusBadRequest, "locker_id conflicts with target_type/target_id")
+		}
+		f.targetType, f.targetID = audit.TargetLocker, strconv.Itoa(id)
+	}
+	if f.targetID != "" && f.targetType == "" {
+		return f, fiber.NewError(fiber.StatusBadRequest, "target_id requires target_type")
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &f.from}, {"to", &f.to}} {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := parseAuditTime(raw)
+		if err != nil {
+			return f, fiber.NewError(fiber.StatusBadRequest, "invalid "+p.name+": use RFC3339 or YYYY-MM-DD")
+		}
+		*p.dst = &t
+	}
+	if f.from != nil && f.to != nil && !f.from.Before(*f.to) {
+		return f, fiber.NewError(fiber.StatusBadRequest, "from must be before to")
+	}
+
+	switch c.Query("order", defaultOrder) {
+	case "desc":
+	case "asc":
+		f.asc = true
+	default:
+		return f, fiber.NewError(fiber.StatusBadRequest, "invalid order: must be asc or desc")
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 1 {
+			return f, fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+		}
+		f.cursor = &v
+	}
+	if raw := c.Query("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			return f, fiber.NewError(fiber.StatusBadRequest, "invalid limit")
+		}
+		f.limit = v
+	}
+	f.limit = min(f.limit, maxLimit)
+	return f, nil
+}
+
+// parseAuditTime: RFC3339 또는 날짜(서버 시간대 자정)
+func parseAuditTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", raw, time.Local)
+}
+
+// query: 필터에 맞는 이벤트 (limit개, event_id 순)
+// - occurred_at은 TIMESTAMP(서버 시간대)이므로 from/to를 같은 시간대의 TIMESTAMP로 바꿔 인덱스를 탄다.
+func (f auditFilter) query(c *fiber.Ctx, d Deps) ([]AuditEventResponse, error) {
+	var exact, prefixes []string
+	for _, a := range f.actions {
+		if strings.HasSuffix(a, ".") {
+			prefixes = append(prefixes, likeEscaper.Replace(a)+"%")
+		} else {
+			exact = append(exact, a)
+		}
+	}
+	order, cmp := "DESC", "<"
+	if f.asc {
+		order, cmp = "ASC", ">"
+	}
+
+	rows, err := d.DB.Query(c.Context(),
+		`SELECT event_id, occurred_at::timestamptz, actor_serial_id, actor_role, action, target_type, target_id,
+		        before_state, after_state, ip, user_agent
+		   FROM audit_events
+		  WHERE ($1::bigint IS NULL OR actor_serial_id = $1)
+		    AND ($2 = '' OR actor_role = $2)
+		    AND (cardinality($3::text[]) + cardinality($4::text[]) = 0 OR action = ANY($3) OR action LIKE ANY($4))
+		    AND ($5 = '' OR target_type = $5)
+		    AND ($6 = '' OR target_id = $6)
+		    AND ($7::timestamptz IS NULL OR occurred_at >= $7::timestamptz::timestamp)
+		    AND ($8::timestamptz IS NULL OR occurred_at < $8::timestamptz::timestamp)
+		    AND ($9::bigint IS NULL OR event_id `+cmp+` $9)
+		  ORDER BY event_id `+order+`
+		  LIMIT $10`,
+		f.actorSerialID, f.actorRole, nonNilStrings(exact), nonNilStrings(prefixes), f.targetType, f.targetID,
+		f.from, f.to, f.cursor, f.limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []AuditEventResponse{}
+	for rows.Next() {
+		var e AuditEventResponse
+		if err := rows.Scan(&e.EventID, &e.OccurredAt, &e.ActorSerialID, &e.ActorRole, &e.Action, &e.TargetType, &e.TargetID,
+			&e.Before, &e.After, &e.IP, &e.UserAgent); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// likeEscaper LIKE 패턴 특수문자 이스케이프 (동작 이름에 '_'가 들어 있다)
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// nonNilStrings: nil 슬라이스는 NULL로 넘어가므로 빈 배열로
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// ListAuditEvents godoc
+// @Summary      감사 로그 조회 (관리자)
+// @Description  사물함 상태 변경, 로그인/로그아웃/refresh, 세션 revoke, 프로필 변경, 관리자 작업의 감사 로그를 조회합니다. 행위자, 동작(쉼표 구분, "locker."처럼 점으로 끝나면 접두사), 대상, 기간으로 거를 수 있고 event_id 기준 cursor 페이지네이션합니다. 특정 사물함의 이력은 locker_id=101&order=asc 처럼 조회합니다.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        actor_serial_id query int false "행위자 serial_id"
+// @Param        actor_role query string false "행위자 구분" Enums(user, admin, system)
+// @Param        action query string false "동작 (쉼표 구분, 예: locker.hold,locker.claim 또는 auth.)"
+// @Param        target_type query string false "대상 종류" Enums(locker, user, session, location, import, eligibility_exception)
+// @Param        target_id query string false "대상 ID (target_type 필요)"
+// @Param        locker_id query int false "target_type=locker&target_id=... 의 줄임"
+// @Param        from query string false "시작 시각 (RFC3339 또는 YYYY-MM-DD, 포함)"
+// @Param        to query string false "끝 시각 (RFC3339 또는 YYYY-MM-DD, 미포함)"
+// @Param        order query string false "event_id 정렬" Enums(desc, asc) default(desc)
+// @Param        cursor query int false "이전 응답의 next_cursor"
+// @Param        limit query int false "페이지 크기 (최대 500)" default(100)
+// @Success      200 {object} ListAuditEventsResponse
+// @Failure      400 {object} ErrorResponse "invalid filter"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/audit [get]
+func ListAuditEvents(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		f, err := parseAuditFilter(c, "desc", defaultAuditPageSize, maxAuditPageSize)
+		if err != nil {
+			return err
+		}
+		events, err := f.query(c, d)
+		if err != nil {
+			log.Printf("ListAuditEvents: query failed: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		out := ListAuditEventsResponse{Events: events}
+		if len(events) == f.limit {
+			next := strconv.FormatInt(events[len(events)-1].EventID, 10)
+			out.NextCursor = &next
+		}
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.JSON(out)
+	}
+}
+
+// auditExportHeader 내보내기 열 이름 (auditRecord와 같은 순서)
+var auditExportHeader = []string{
+	"event_id", "occurred_at", "actor_serial_id", "actor_role", "action", "target_type", "target_id",
+	"before", "after", "ip", "user_agent",
+}
+
+// auditRecord: 이벤트 한 건 → 행 (user_agent, before/after는 요청자가 넣은 값이 그대로 들어가므로
+// 수식 주입 방지는 bulk.WriteTable의 셀 escape에 맡긴다)
+func auditRecord(e AuditEventResponse) []string {
+	str := func(p *string) string {
+		if p == nil {
+			return ""
+		}
+		return *p
+	}
+	actor := ""
+	if e.ActorSerialID != nil {
+		actor = strconv.FormatInt(*e.ActorSerialID, 10)
+	}
+	return []string{
+		strconv.FormatInt(e.EventID, 10), formatExportTime(&e.OccurredAt), actor, e.ActorRole, e.Action, e.TargetType, str(e.TargetID),
+		string(e.Before), string(e.After), str(e.IP), str(e.UserAgent),
+	}
+}
+
+// ExportAuditEvents godoc
+// @Summary      감사 로그 내보내기 (관리자)
+// @Description  GET /admin/audit과 같은 필터로 감사 로그를 CSV 또는 XLSX 파일로 내려받습니다. 기본은 시간 순(order=asc)이며 한 번에 최대 100000건입니다. before/after는 JSON 문자열로 들어갑니다. =, +, -, @, 탭, CR로 시작하는 셀(User-Agent 등)은 수식으로 실행되지 않도록 앞에 '가 붙습니다.
+// @Tags         admin
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        format query string false "파일 형식" Enums(csv, xlsx) default(csv)
+// @Param        actor_serial_id query int false "행위자 serial_id"
+// @Param        actor_role query string false "행위자 구분" Enums(user, admin, system)
+// @Param        action query string false "동작 (쉼표 구분, 점으로 끝나면 접두사)"
+// @Param        target_type query string false "대상 종류" Enums(locker, user, session, location, import, eligibility_exception)
+// @Param        target_id query string false "대상 ID (target_type 필요)"
+// @Param        locker_id query int false "target_type=locker&target_id=... 의 줄임"
+// @Param        from query string false "시작 시각 (RFC3339 또는 YYYY-MM-DD, 포함)"
+// @Param        to query string false "끝 시각 (RFC3339 또는 YYYY-MM-DD, 미포함)"
+// @Param        order query string false "event_id 정렬" Enums(asc, desc) default(asc)
+// @Param        cursor query int false "이 event_id 다음부터"
+// @Param        limit query int false "최대 건수 (최대 100000)" default(100000)
+// @Success      200 {file} file "감사 로그 파일"
+// @Failure      400 {object} ErrorResponse "unknown format / invalid filter"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/audit/export [get]
+func ExportAuditEvents(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format, err := bulk.ParseFormat(c.Query("format"))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		// 파일은 시간 순이 기본
+		f, err := parseAuditFilter(c, "asc", maxAuditExportRows, maxAuditExportRows)
+		if err != nil {
+			return err
+		}
+		events, err := f.query(c, d)
+		if err != nil {
+			log.Printf("ExportAuditEvents: query failed: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		records := make([][]string, 0, len(events))
+		for _, e := range events {
+			records = append(records, auditRecord(e))
+		}
+		var buf bytes.Buffer
+		if err := bulk.WriteTable(&buf, format, "audit", auditExportHeader, records); err != nil {
+			log.Printf("ExportAuditEvents: failed to write %s: %v", format, err)
+			return fiber.ErrInternalServerError
+		}
+
+		adminSerial, _ := c.Locals("user_serial_id").(int64)
+		log.Printf("ExportAuditEvents: %d events exported as %s by admin %d", len(records), format, adminSerial)
+
+		filename := fmt.Sprintf("audit-%s.%s", time.Now().Format("20060102-1504"), format)
+		c.Set(fiber.HeaderContentType, format.ContentType())
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.Send(buf.Bytes())
+	}
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/bulk"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 학기 준비용 일괄 가져오기/내보내기 (관리자)
+// - 가져오기: 위치/사물함/신청 자격 명단 CSV (internal/bulk, CLI `server import`와 같은 코드)
+// - 내보내기: 현재 배정 + 사용자 정보를 CSV/XLSX로 (학생회 기록용)
+
+// AssignmentExportRow 내보내기 한 행 (사용자 필드는 User와 같음)
+type AssignmentExportRow struct {
+	User
+	LockerID     int        `json:"locker_id" example:"101"`
+	LocationName string     `json:"location_name" example:"정보관 B1"`
+	State        string     `json:"state" example:"confirmed"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+	ReleasedAt   *time.Time `json:"released_at"`
+}
+
+// assignmentExportHeader 내보내기 열 이름 (record와 같은 순서)
+var assignmentExportHeader = []string{
+	"locker_id", "location", "state", "created_at", "confirmed_at", "released_at",
+	"serial_id", "student_id", "name", "phone_number",
+}
+
+func (r AssignmentExportRow) record() []string {
+	return []string{
+		strconv.Itoa(r.LockerID), r.LocationName, r.State,
+		formatExportTime(&r.CreatedAt), formatExportTime(r.ConfirmedAt), formatExportTime(r.ReleasedAt),
+		strconv.FormatInt(r.SerialID, 10), r.StudentID, r.Name, r.PhoneNumber,
+	}
+}
+
+// formatExportTime: 서버 시간대(Asia/Seoul) 기준 "2006-01-02 15:04:05", nil이면 빈 칸
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// 내보내기 상태 필터
+var assignmentExportStates = map[string][]string{
+	"active":    {"hold", "confirmed"},
+	"confirmed": {"confirmed"},
+	"all":       nil,
+}
+
+// ImportData godoc
+// @Summary      CSV 일괄 가져오기 (관리자)
+// @Description  위치(locations: name, grid_rows, grid_cols), 사물함(lockers: locker_id, location 또는 location_id, size, row, near_outlet, out_of_service), 신청 자격 명단(roster: student_id, name, department, note, fee_paid)을 CSV로 가져옵니다. 첫 줄은 헤더입니다. 행마다 검증하고 에러를 줄 번호와 함께 보고하며, 에러가 하나라도 있으면 아무것도 반영하지 않습니다. dry_run=true면 DB 반영까지 시험해 보고 롤백합니다. replace=true(명단만)면 파일에 없는 학생을 명단에서 제거합니다. 본문은 CSV 그대로(text/csv) 또는 multipart의 file 필드로 보냅니다.
+// @Tags         admin
+// @Accept       text/csv
+// @Accept       mpfd
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        kind path string true "가져올 대상" Enums(locations, lockers, roster)
+// @Param        dry_run query bool false "true면 검증/시험 반영 후 롤백"
+// @Param        replace query bool false "(roster) 파일에 없는 학생 제거"
+// @Param        file formData file false "CSV 파일 (multipart로 보낼 때)"
+// @Success      200 {object} bulk.Report "반영(또는 dry run) 성공"
+// @Failure      400 {object} ErrorResponse "unknown import kind / replace is only supported for roster imports / missing csv"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      422 {object} bulk.Report "행 에러 - 아무것도 반영되지 않음"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/import/{kind} [post]
+func ImportData(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		kind, err := bulk.ParseKind(c.Params("kind"))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		ev := requestAudit(c)
+		opts := bulk.Options{DryRun: c.QueryBool("dry_run"), Replace: c.QueryBool("replace"), Audit: &ev}
+
+		// 본문: multipart file 필드 또는 CSV 그대로
+		var src io.Reader = bytes.NewReader(c.Body())
+		if fh, err := c.FormFile("file"); err == nil {
+			f, err := fh.Open()
+			if err != nil {
+				return fiber.ErrBadRequest
+			}
+			defer f.Close()
+			src = f
+		} else if len(c.Body()) == 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "missing csv: send it as the request body or as multipart field \"file\"")
+		}
+
+		rep, err := bulk.Import(c.Context(), d.DB, kind, src, opts)
+		if errors.Is(err, bulk.ErrReplaceNotSupported) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if err != nil {
+			log.Printf("ImportData: %s import failed: %v", kind, err)
+			return fiber.ErrInternalServerError
+		}
+
+		adminSerial, _ := c.Locals("user_serial_id").(int64)
+		log.Printf("ImportData: %s import by admin %d (dry_run=%t, replace=%t): rows=%d inserted=%d updated=%d removed=%d errors=%d applied=%t",
+			kind, adminSerial, opts.DryRun, opts.Replace, rep.Rows, rep.Inserted, rep.Updated, rep.Removed, len(rep.Errors), rep.Applied)
+
+		if !rep.OK() {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(rep)
+		}
+		if rep.Applied && kind != bulk.KindRoster {
+			d.Lockers.Publish(c.Context(), 0, lockercache.EventAdmin)
+		}
+		return c.JSON(rep)
+	}
+}
+
+// ExportAssignments godoc
+// @Summary      배정 내보내기 (관리자)
+// @Description  사물함 배정을 사용자 정보(serial_id, 학번, 이름, 전화번호)와 함께 CSV 또는 XLSX 파일로 내려받습니다. state=active(기본, hold+confirmed), confirmed, all 중 하나를 고릅니다.
+// @Tags         admin
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        format query string false "파일 형식" Enums(csv, xlsx) default(csv)
+// @Param        state query string false "배정 상태" Enums(active, confirmed, all) default(active)
+// @Success      200 {file} file "배정 목록 파일"
+// @Failure      400 {object} ErrorResponse "unknown format / invalid state"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/export/assignments [get]
+func ExportAssignments(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format, err := bulk.ParseFormat(c.Query("format"))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		stateName := c.Query("state", "active")
+		states, ok := assignmentExportStates[stateName]
+		if !ok {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid state: must be active, confirmed or all")
+		}
+
+		rows, err := d.DB.Query(c.Context(),
+			`SELECT a.locker_id, ll.name, a.state::text,
+			        a.created_at::timestamptz, a.confirmed_at::timestamptz, a.released_at::timestamptz,
+			        u.serial_id, u.student_id, u.name, u.phone_number
+			   FROM locker_assignments a
+			   JOIN locker_info l ON l.locker_id = a.locker_id
+			   JOIN locker_locations ll ON ll.location_id = l.location_id
+			   JOIN users u ON u.serial_id = a.user_serial_id
+			  WHERE $1::text[] IS NULL OR a.state::text = ANY($1)
+			  ORDER BY a.locker_id, a.created_at`,
+			states)
+		if err != nil {
+			log.Printf("ExportAssignments: query failed: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		defer rows.Close()
+
+		var records [][]string
+		for rows.Next() {
+			var r AssignmentExportRow
+			if err := rows.Scan(&r.LockerID, &r.LocationName, &r.State,
+				&r.CreatedAt, &r.ConfirmedAt, &r.ReleasedAt,
+				&r.SerialID, &r.StudentID, &r.Name, &r.PhoneNumber); err != nil {
+				log.Printf("ExportAssignments: scan failed: %v", err)
+				return fiber.ErrInternalServerError
+			}
+			records = append(records, r.record())
+		}
+		if err := rows.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		var buf bytes.Buffer
+		if err := bulk.WriteTable(&buf, format, "assignments", assignmentExportHeader, records); err != nil {
+			log.Printf("ExportAssignments: failed to write %s: %v", format, err)
+			return fiber.ErrInternalServerError
+		}
+
+		adminSerial, _ := c.Locals("user_serial_id").(int64)
+		log.Printf("ExportAssignments: %d %s assignments exported as %s by admin %d", len(records), stateName, format, adminSerial)
+
+		filename := fmt.Sprintf("assignments-%s-%s.%s", stateName, time.Now().Format("20060102-1504"), format)
+		c.Set(fiber.HeaderContentType, format.ContentType())
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.Send(buf.Bytes())
+	}
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/eligibility"
+	"github.com/KUCSEPotato/locker-server/internal/round"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// 신청 자격 예외 관리 (관리자)
+// - 명단 누락, 편입생 학번 등 규칙으로 걸러지는 학생을 개별로 허용한다.
+// - round_id를 비우면 모든 회차에 적용된다.
+
+// EligibilityException 자격 예외 한 건
+type EligibilityException struct {
+	ExceptionID int64     `json:"exception_id" example:"1"`
+	StudentID   string    `json:"student_id" example:"2025320000"`
+	RoundID     *int64    `json:"round_id" example:"3"` // null이면 모든 회차
+	Reason      string    `json:"reason" example:"편입생 (명단 누락)"`
+	GrantedBy   *int64    `json:"granted_by" example:"123456789012"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// EligibilityExceptionRequest 자격 예외 추가 요청
+type EligibilityExceptionRequest struct {
+	StudentID string `json:"student_id" example:"2025320000"`
+	RoundID   *int64 `json:"round_id" example:"3"` // 생략하면 모든 회차
+	Reason    string `json:"reason" example:"편입생 (명단 누락)"`
+}
+
+// EligibilityCheckResponse 자격 검사 결과 (현재 회차)
+type EligibilityCheckResponse struct {
+	RoundID   int64  `json:"round_id" example:"3"`
+	StudentID string `json:"student_id" example:"2025320000"`
+	eligibility.Decision
+}
+
+var studentIDFormat = regexp.MustCompile(`^\d{10}$`)
+
+// ListEligibilityExceptions godoc
+// @Summary      신청 자격 예외 목록 (관리자)
+// @Description  관리자가 허용한 학생별 신청 자격 예외를 최신순으로 반환합니다. student_id로 거를 수 있습니다.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        student_id query string false "학번"
+// @Success      200 {array} EligibilityException
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/eligibility/exceptions [get]
+func ListEligibilityExceptions(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rows, err := d.DB.Query(c.Context(),
+			`SELECT exception_id, student_id, round_id, reason, granted_by, created_at::timestamptz
+			   FROM eligibility_exceptions
+			  WHERE $1 = '' OR student_id = $1
+			  ORDER BY created_at DESC, exception_id DESC`,
+			strings.TrimSpace(c.Query("student_id")))
+		if err != nil {
+			log.Printf("ListEligibilityExceptions: query failed: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		defer rows.Close()
+
+		out := []EligibilityException{}
+		for rows.Next() {
+			var e EligibilityException
+			if err := rows.Scan(&e.ExceptionID, &e.StudentID, &e.RoundID, &e.Reason, &e.GrantedBy, &e.CreatedAt); err != nil {
+				return fiber.ErrInternalServerError
+			}
+			out = append(out, e)
+		}
+		if err := rows.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		return c.JSON(out)
+	}
+}
+
+// GrantEligibilityException godoc
+// @Summary      신청 자격 예외 추가 (관리자)
+// @Description  학생 한 명을 회차(round_id, 생략하면 모든 회차)의 신청 자격 규칙에서 제외합니다. 같은 학생/회차의 예외가 이미 있으면 사유만 바꿉니다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        payload body EligibilityExceptionRequest true "예외 정보"
+// @Success      201 {object} EligibilityException
+// @Failure      400 {object} ErrorResponse "invalid student_id format / reason is required / unknown round_id"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/eligibility/exceptions [post]
+func GrantEligibilityException(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req EligibilityExceptionRequest
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.ErrBadRequest
+		}
+		req.StudentID = strings.TrimSpace(req.StudentID)
+		req.Reason = strings.TrimSpace(req.Reason)
+		if !studentIDFormat.MatchString(req.StudentID) {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid student_id format")
+		}
+		if req.Reason == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "reason is required")
+		}
+		adminSerial, _ := c.Locals("user_serial_id").(int64)
+
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		// 같은 학번/회차 예외가 이미 있으면 덮어쓴다 → 감사 로그용 이전 값
+		var (
+			prev   EligibilityException
+			before *EligibilityException
+		)
+		err = tx.QueryRow(c.Context(),
+			`SELECT exception_id, student_id, round_id, reason, granted_by, created_at::timestamptz
+			   FROM eligibility_exceptions
+			  WHERE student_id = $1 AND COALESCE(round_id, 0) = COALESCE($2, 0)
+			  FOR UPDATE`,
+			req.StudentID, req.RoundID,
+		).Scan(&prev.ExceptionID, &prev.StudentID, &prev.RoundID, &prev.Reason, &prev.GrantedBy, &prev.CreatedAt)
+		switch {
+		case err == nil:
+			before = &prev
+		case !errors.Is(err, pgx.ErrNoRows):
+			log.Printf("GrantEligibilityException: lookup failed: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		var e EligibilityException
+		err = tx.QueryRow(c.Context(),
+			`INSERT INTO eligibility_exceptions (student_id, round_id, reason, granted_by)
+			 VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (student_id, (COALESCE(round_id, 0))) DO UPDATE
+			    SET reason = EXCLUDED.reason, granted_by = EXCLUDED.granted_by, created_at = now()
+			 RETURNING exception_id, student_id, round_id, reason, granted_by, created_at::timestamptz`,
+			req.StudentID, req.RoundID, req.Reason, adminSerial,
+		).Scan(&e.ExceptionID, &e.StudentID, &e.RoundID, &e.Reason, &e.GrantedBy, &e.CreatedAt)
+		if err != nil {
+			// 없는 회차 (FK 위반)
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return fiber.NewError(fiber.StatusBadRequest, "unknown round_id")
+			}
+			log.Printf("GrantEligibilityException: insert failed: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.AdminEligibilityGrant, audit.TargetEligibility, strconv.FormatInt(e.ExceptionID, 10)).
+			Change(before, e)); err != nil {
+			log.Printf("GrantEligibilityException: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		log.Printf("GrantEligibilityException: student %s, round %s granted by admin %d: %s", e.StudentID, formatRoundID(e.RoundID), adminSerial, e.Reason)
+		return c.Status(fiber.StatusCreated).JSON(e)
+	}
+}
+
+// RevokeEligibilityException godoc
+// @Summary      신청 자격 예외 삭제 (관리자)
+// @Description  자격 예외를 삭제합니다. 이미 받은 사물함에는 영향이 없고, 이후 로그인/hold부터 규칙이 다시 적용됩니다.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        id path int true "exception_id"
+// @Success      204 "삭제됨"
+// @Failure      400 {object} ErrorResponse "bad request"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      404 {object} ErrorResponse "exception not found"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/eligibility/exceptions/{id} [delete]
+func RevokeEligibilityException(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.ErrBadRequest
+		}
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		var e EligibilityException
+		err = tx.QueryRow(c.Context(),
+			`DELETE FROM eligibility_exceptions WHERE exception_id = $1
+			 RETURNING exception_id, student_id, round_id, reason, granted_by, created_at::timestamptz`, id,
+		).Scan(&e.ExceptionID, &e.StudentID, &e.RoundID, &e.Reason, &e.GrantedBy, &e.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "exception not found")
+		}
+		if err != nil {
+			log.Printf("RevokeEligibilityException: delete failed: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.AdminEligibilityRevoke, audit.TargetEligibility, strconv.FormatInt(id, 10)).
+			Change(e, nil)); err != nil {
+			log.Printf("RevokeEligibilityException: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		adminSerial, _ := c.Locals("user_serial_id").(int64)
+		log.Printf("RevokeEligibilityException: exception %d revoked by admin %d", id, adminSerial)
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// CheckEligibility godoc
+// @Summary      신청 자격 확인 (관리자)
+// @Description  학번(과 선택적으로 이름)이 현재 회차의 신청 자격 규칙을 통과하는지, 막힌다면 어떤 사유인지 보여줍니다. 예외로 통과하면 exception=true입니다.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        student_id query string true "학번"
+// @Param        name query string false "이름 (명단 이름과 비교)"
+// @Success      200 {object} EligibilityCheckResponse
+// @Failure      400 {object} ErrorResponse "invalid student_id format"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/eligibility/check [get]
+func CheckEligibility(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		studentID := strings.TrimSpace(c.Query("student_id"))
+		if !studentIDFormat.MatchString(studentID) {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid student_id format")
+		}
+		rd, err := round.Current(c.Context(), d.DB)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		dec, err := eligibility.Check(c.Context(), d.DB, rd.ID, rd.Eligibility, studentID, strings.TrimSpace(c.Query("name")))
+		if err != nil {
+			log.Printf("CheckEligibility: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		return c.JSON(EligibilityCheckResponse{RoundID: rd.ID, StudentID: studentID, Decision: dec})
+	}
+}
+
+// formatRoundID: 로그용 ("all" 또는 회차 번호)
+func formatRoundID(id *int64) string {
+	if id == nil {
+		return "all"
+	}
+	return strconv.FormatInt(*id, 10)
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+// 배치도 격자 최대 크기
+const maxLayoutSize = 50
+
+// LayoutPosition 배치도에서 사물함 하나의 칸
+type LayoutPosition struct {
+	LockerID int `json:"locker_id" example:"101"`
+	Row      int `json:"row" example:"1"`
+	Col      int `json:"col" example:"3"`
+}
+
+// UpdateLocationLayoutRequest 위치 배치도 교체 요청
+// - positions에 없는 이 위치의 사물함은 배치 해제된다 (unplaced)
+type UpdateLocationLayoutRequest struct {
+	Rows      int              `json:"rows" example:"4"`
+	Cols      int              `json:"cols" example:"15"`
+	Positions []LayoutPosition `json:"positions"`
+}
+
+// UpdateLocationLayout godoc
+// @Summary      위치 배치도 교체 (관리자)
+// @Description  위치의 격자 크기(rows×cols, 최대 50×50)와 사물함별 칸(row/col, 1부터)을 통째로 교체합니다. positions에 없는 이 위치의 사물함은 배치 해제됩니다. 다른 위치의 사물함, 격자 밖 칸, 한 칸에 두 사물함은 400입니다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        id path int true "위치 ID" example(1)
+// @Param        payload body UpdateLocationLayoutRequest true "배치도"
+// @Success      200 {object} LocationLayout
+// @Failure      400 {object} ErrorResponse "invalid layout"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      404 {object} ErrorResponse "location not found"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/locations/{id}/layout [put]
+func UpdateLocationLayout(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		locationID, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		var req UpdateLocationLayoutRequest
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.ErrBadRequest
+		}
+
+		// 격자/칸 검증 (사물함 소속은 DB에서 확인)
+		if req.Rows < 1 || req.Cols < 1 || req.Rows > maxLayoutSize || req.Cols > maxLayoutSize {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("rows and cols must be between 1 and %d", maxLayoutSize))
+		}
+		lockerIDs := make([]int, 0, len(req.Positions))
+		rows := make([]int, 0, len(req.Positions))
+		cols := make([]int, 0, len(req.Positions))
+		seenLocker := map[int]bool{}
+		seenCell := map[[2]int]int{}
+		for _, p := range req.Positions {
+			if p.Row < 1 || p.Row > req.Rows || p.Col < 1 || p.Col > req.Cols {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("locker %d is outside the %dx%d grid", p.LockerID, req.Rows, req.Cols))
+			}
+			if seenLocker[p.LockerID] {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("locker %d is placed more than once", p.LockerID))
+			}
+			if other, ok := seenCell[[2]int{p.Row, p.Col}]; ok {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("lockers %d and %d share cell (%d, %d)", other, p.LockerID, p.Row, p.Col))
+			}
+			seenLocker[p.LockerID] = true
+			seenCell[[2]int{p.Row, p.Col}] = p.LockerID
+			lockerIDs = append(lockerIDs, p.LockerID)
+			rows = append(rows, p.Row)
+			cols = append(cols, p.Col)
+		}
+
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		// 1) 위치 격자 크기 (행 잠금 겸 존재 확인, 감사 로그용 이전 크기도 반환)
+		var prevRows, prevCols *int
+		err = tx.QueryRow(c.Context(),
+			`UPDATE locker_locations l SET grid_rows = $2, grid_cols = $3
+			   FROM locker_locations prev
+			  WHERE l.location_id = $1 AND prev.location_id = l.location_id
+			RETURNING prev.grid_rows, prev.grid_cols`,
+			locationID, req.Rows, req.Cols).Scan(&prevRows, &prevCols)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "location not found")
+		}
+		if err != nil {
+			log.Printf("UpdateLocationLayout: failed to update grid for location %d: %v", locationID, err)
+			return fiber.ErrInternalServerError
+		}
+
+		// 2) 모든 사물함이 이 위치 소속인지 확인
+		var foreign []int
+		rowsIt, err := tx.Query(c.Context(),
+			`SELECT id FROM unnest($2::int[]) AS id
+			  WHERE NOT EXISTS (SELECT 1 FROM locker_info WHERE locker_id = id AND location_id = $1)`,
+			locationID, lockerIDs)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		for rowsIt.Next() {
+			var id int
+			if err := rowsIt.Scan(&id); err != nil {
+				rowsIt.Close()
+				return fiber.ErrInternalServerError
+			}
+			foreign = append(foreign, id)
+		}
+		rowsIt.Close()
+		if err := rowsIt.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		if len(foreign) > 0 {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("lockers %v do not belong to location %d", foreign, locationID))
+		}
+
+		// 3) 이 위치의 배치를 모두 지우고 새로 지정 (칸 유니크 인덱스 충돌을 피하려고 두 단계)
+		//    - 지우면서 이전 배치를 돌려받아 감사 로그에 남긴다
+		prevPositions := []LayoutPosition{}
+		rowsIt, err = tx.Query(c.Context(),
+			`UPDATE locker_info l SET grid_row = NULL, grid_col = NULL
+			   FROM locker_info prev
+			  WHERE l.location_id = $1 AND prev.locker_id = l.locker_id AND prev.grid_row IS NOT NULL
+			RETURNING prev.locker_id, prev.grid_row, prev.grid_col`, locationID)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		for rowsIt.Next() {
+			var p LayoutPosition
+			if err := rowsIt.Scan(&p.LockerID, &p.Row, &p.Col); err != nil {
+				rowsIt.Close()
+				return fiber.ErrInternalServerError
+			}
+			prevPositions = append(prevPositions, p)
+		}
+		rowsIt.Close()
+		if err := rowsIt.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		if _, err := tx.Exec(c.Context(),
+			`UPDATE locker_info l SET grid_row = p.row, grid_col = p.col
+			   FROM unnest($1::int[], $2::int[], $3::int[]) AS p(locker_id, row, col)
+			  WHERE l.locker_id = p.locker_id`,
+			lockerIDs, rows, cols); err != nil {
+			log.Printf("UpdateLocationLayout: failed to place lockers for location %d: %v", locationID, err)
+			return fiber.ErrInternalServerError
+		}
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.AdminLocationLayout, audit.TargetLocation, strconv.Itoa(locationID)).
+			Change(map[string]any{"rows": prevRows, "cols": prevCols, "positions": prevPositions}, req)); err != nil {
+			log.Printf("UpdateLocationLayout: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		d.Lockers.Publish(c.Context(), 0, lockercache.EventAdmin)
+		log.Printf("UpdateLocationLayout: location %d layout set to %dx%d with %d lockers", locationID, req.Rows, req.Cols, len(lockerIDs))
+
+		// 갱신된 스냅샷 기준으로 응답 (상태 포함)
+		snap, err := d.Lockers.Get(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		for _, sum := range summarizeLocations(snap, time.Now()) {
+			if sum.LocationID == locationID && sum.Layout != nil {
+				return c.JSON(sum.Layout)
+			}
+		}
+		return fiber.ErrInternalServerError
+	}
+}
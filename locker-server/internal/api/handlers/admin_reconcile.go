@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/KUCSEPotato/locker-server/internal/reconcile"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RunReconcileRequest 정합성 검사 실행 요청
+type RunReconcileRequest struct {
+	Repair bool `json:"repair" example:"false"` // true면 안전하게 고칠 수 있는 항목은 바로 고침
+}
+
+// GetReconcileReport godoc
+// @Summary      hold/배정 정합성 검사 결과 조회 (관리자)
+// @Description  주기적으로 실행되는 정합성 검사(Redis hold 키 ↔ locker_assignments ↔ locker_info)의 마지막 결과를 반환합니다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Success      200 {object} reconcile.Report
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      404 {object} ErrorResponse "no reconcile report yet"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/reconcile [get]
+func GetReconcileReport(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var rep *reconcile.Report
+		rep, err := d.Reconciler.LastReport(c.Context())
+		if err != nil {
+			log.Printf("GetReconcileReport: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		if rep == nil {
+			return fiber.NewError(fiber.StatusNotFound, "no reconcile report yet")
+		}
+		return c.JSON(rep)
+	}
+}
+
+// RunReconcile godoc
+// @Summary      hold/배정 정합성 검사 즉시 실행 (관리자)
+// @Description  Redis hold 키, locker_assignments, locker_info 사이의 불변식을 지금 검사합니다. repair=false(기본)면 보고만 하고, true면 orphan 키 삭제/만료 처리/owner 보정 등 안전한 복구를 수행합니다. owner 충돌처럼 판단이 필요한 항목은 항상 보고만 합니다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        payload body RunReconcileRequest false "실행 옵션"
+// @Success      200 {object} reconcile.Report
+// @Failure      400 {object} ErrorResponse "invalid request body"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/reconcile [post]
+func RunReconcile(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req RunReconcileRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return fiber.ErrBadRequest
+			}
+		}
+
+		rep, err := d.Reconciler.Run(c.Context(), req.Repair)
+		if err != nil {
+			log.Printf("RunReconcile: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		log.Printf("RunReconcile: admin %d ran reconcile (repair=%t): %v", serialID, req.Repair, rep.Counts)
+		return c.JSON(rep)
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/KUCSEPotato/locker-server/internal/serial"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RekeySerialsRequest legacy serial_id 재발급 요청
+type RekeySerialsRequest struct {
+	DryRun        bool  `json:"dry_run" example:"true"`
+	Limit         int   `json:"limit" example:"100"`                    // 한 번에 처리할 최대 계정 수 (0이면 100)
+	AfterSerialID int64 `json:"after_serial_id" example:"123456789012"` // 이 serial_id 다음부터 (이전 응답의 next_after_serial_id, 0이면 처음부터)
+}
+
+// RekeyedSerial 재발급 한 건 (old → new)
+type RekeyedSerial struct {
+	OldSerialID int64  `json:"old_serial_id" example:"123456789012"`
+	NewSerialID int64  `json:"new_serial_id" example:"987654321098"`
+	StudentID   string `json:"student_id" example:"2025320000"`
+}
+
+// RekeySerialsResponse 재발급 결과
+type RekeySerialsResponse struct {
+	DryRun            bool            `json:"dry_run" example:"true"`
+	Rekeyed           []RekeyedSerial `json:"rekeyed"`
+	Skipped           []int64         `json:"skipped"`                                     // 충돌 없는 후보가 없어 건너뛴 계정
+	Remaining         int             `json:"remaining"`                                   // 아직 legacy 스킴인 계정 수 (이번 처리 후)
+	Holding           int             `json:"holding"`                                     // remaining 중 활성 hold가 있어 대상에서 빠진 계정 수 (hold가 끝난 뒤 처음부터 다시 실행)
+	NextAfterSerialID *int64          `json:"next_after_serial_id" example:"123456789012"` // 다음 호출의 after_serial_id (이번 배치가 마지막이면 null)
+}
+
+// RekeyLegacySerials godoc
+// @Summary      legacy serial_id 재발급 (관리자)
+// @Description  예전 SHA256 방식으로 만들어진 계정의 serial_id를 HMAC 방식으로 재발급합니다. 참조 테이블은 ON UPDATE CASCADE로 함께 바뀌고, 해당 계정의 세션은 모두 무효화됩니다(토큰 sub가 바뀌므로 재로그인 필요). 활성 hold가 있는 계정은 대상에서 제외하고(holding), 충돌 없는 후보가 없는 계정은 건너뜁니다(skipped). serial_id 순으로 limit개씩 처리하며, 응답의 next_after_serial_id를 after_serial_id로 넘기면 다음 배치를 처리합니다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        payload body RekeySerialsRequest false "재발급 옵션"
+// @Success      200 {object} RekeySerialsResponse
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/users/rekey-serials [post]
+func RekeyLegacySerials(d Deps) fiber.Handler {
+	serials, err := serial.NewAllocatorFromEnv()
+	if err != nil {
+		log.Fatalf("RekeyLegacySerials: %v", err)
+	}
+
+	return func(c *fiber.Ctx) error {
+		var req RekeySerialsRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return fiber.ErrBadRequest
+			}
+		}
+		if req.Limit <= 0 {
+			req.Limit = 100
+		}
+
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		// 1) 대상 계정 잠금: legacy 스킴 + 탈퇴 안 함 + after_serial_id 이후
+		// - hold 중인 계정은 Redis hold 키에 옛 serial_id가 들어 있으므로 처음부터 제외 (매 배치가 같은 계정에 막히지 않도록)
+		type legacyUser struct {
+			serialID            int64
+			studentID, name, ph string
+		}
+		rows, err := tx.Query(c.Context(),
+			`SELECT u.serial_id, u.student_id, u.name, u.phone_number
+			   FROM users u
+			  WHERE u.serial_scheme = $1 AND u.deleted_at IS NULL
+			    AND u.serial_id > $2
+			    AND NOT EXISTS (SELECT 1 FROM locker_assignments a
+			                     WHERE a.user_serial_id = u.serial_id AND a.state = 'hold')
+			  ORDER BY u.serial_id
+			  LIMIT $3
+			  FOR UPDATE OF u`,
+			serial.SchemeLegacySHA256, req.AfterSerialID, req.Limit)
+		if err != nil {
+			log.Printf("RekeyLegacySerials: failed to load legacy users: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		var targets []legacyUser
+		for rows.Next() {
+			var u legacyUser
+			if err := rows.Scan(&u.serialID, &u.studentID, &u.name, &u.ph); err != nil {
+				rows.Close()
+				return fiber.ErrInternalServerError
+			}
+			targets = append(targets, u)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		out := RekeySerialsResponse{DryRun: req.DryRun, Rekeyed: []RekeyedSerial{}, Skipped: []int64{}}
+		var jtis []string
+
+		// 배치가 꽉 찼으면 뒤에 더 있을 수 있음 → 건너뛴 계정이 있어도 다음 배치는 그 뒤부터
+		if len(targets) == req.Limit {
+			next := targets[len(targets)-1].serialID
+			out.NextAfterSerialID = &next
+		}
+
+		for _, u := range targets {
+			// 2) 충돌 없는 후보 찾기 (신규 가입과 같은 후보 순서)
+			var newID int64
+			for attempt := 0; attempt < serials.MaxAttempts(); attempt++ {
+				cand := serials.Candidate(u.studentID, u.name, u.ph, attempt)
+				var taken bool
+				if err := tx.QueryRow(c.Context(),
+					`SELECT EXISTS (SELECT 1 FROM users WHERE serial_id = $1)`, cand,
+				).Scan(&taken); err != nil {
+					return fiber.ErrInternalServerError
+				}
+				if !taken {
+					newID = cand
+					break
+				}
+			}
+			if newID == 0 {
+				log.Printf("RekeyLegacySerials: no free candidate for user %d", u.serialID)
+				out.Skipped = append(out.Skipped, u.serialID)
+				continue
+			}
+
+			// 3) serial_id 변경 (FK ON UPDATE CASCADE로 배정/소유/세션이 함께 바뀜)
+			if _, err := tx.Exec(c.Context(),
+				`UPDATE users SET serial_id = $1, serial_scheme = $2, updated_at = now() WHERE serial_id = $3`,
+				newID, serial.SchemeHMAC, u.serialID); err != nil {
+				log.Printf("RekeyLegacySerials: failed to rekey user %d: %v", u.serialID, err)
+				return fiber.ErrInternalServerError
+			}
+
+			// 4) 옛 sub로 발급된 토큰 무효화
+			rows, err := tx.Query(c.Context(),
+				`UPDATE auth_refresh_tokens SET revoked_at = now()
+				  WHERE user_serial_id = $1 AND revoked_at IS NULL
+				RETURNING access_jti`,
+				newID)
+			if err != nil {
+				return fiber.ErrInternalServerError
+			}
+			for rows.Next() {
+				var jti sql.NullString
+				if err := rows.Scan(&jti); err == nil && jti.Valid {
+					jtis = append(jtis, jti.String)
+				}
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return fiber.ErrInternalServerError
+			}
+
+			if err := audit.Record(c.Context(), tx, requestAudit(c).
+				On(audit.AdminSerialRekey, audit.TargetUser, strconv.FormatInt(newID, 10)).
+				Change(map[string]any{"serial_id": u.serialID, "serial_scheme": serial.SchemeLegacySHA256},
+					map[string]any{"serial_id": newID, "serial_scheme": serial.SchemeHMAC})); err != nil {
+				log.Printf("RekeyLegacySerials: %v", err)
+				return fiber.ErrInternalServerError
+			}
+
+			out.Rekeyed = append(out.Rekeyed, RekeyedSerial{OldSerialID: u.serialID, NewSerialID: newID, StudentID: u.studentID})
+		}
+
+		if err := tx.QueryRow(c.Context(),
+			`SELECT COUNT(*),
+			        COUNT(*) FILTER (WHERE EXISTS (SELECT 1 FROM locker_assignments a
+			                                        WHERE a.user_serial_id = u.serial_id AND a.state = 'hold'))
+			   FROM users u
+			  WHERE u.serial_scheme = $1 AND u.deleted_at IS NULL`,
+			serial.SchemeLegacySHA256,
+		).Scan(&out.Remaining, &out.Holding); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		// dry_run이면 defer의 Rollback으로 전부 되돌린다.
+		if req.DryRun {
+			return c.JSON(out)
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		for _, jti := range jtis {
+			blacklistAccessJTI(c.Context(), d.RDB, jti)
+		}
+		// owner_serial_id가 ON UPDATE CASCADE로 바뀌었을 수 있음
+		d.Lockers.Publish(c.Context(), 0, lockercache.EventAdmin)
+
+		adminID, _ := c.Locals("user_serial_id").(int64)
+		log.Printf("RekeyLegacySerials: admin %d rekeyed %d users (skipped %d, holding %d, remaining %d)",
+			adminID, len(out.Rekeyed), len(out.Skipped), out.Holding, out.Remaining)
+		return c.JSON(out)
+	}
+}
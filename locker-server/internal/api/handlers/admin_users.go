@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 중복 계정 탐지 / 병합 (관리자용)
+// - 003 마이그레이션 이후 student_id는 유니크가 아니고, serial_id는 이름+전화번호까지 해시하므로
+//   같은 학생이 오타 하나로 여러 users 행을 가질 수 있다.
+// - 리포트로 후보를 보여주고, 관리자가 남길 계정(survivor)을 골라 병합한다.
+
+// DuplicateUser 중복 후보 그룹의 사용자 한 명
+type DuplicateUser struct {
+	User
+	ActiveLockerID *int `json:"active_locker_id,omitempty" example:"101"` // 현재 소유 중인 사물함
+	Assignments    int  `json:"assignments" example:"3"`                  // 전체 배정 히스토리 수
+	Sessions       int  `json:"sessions" example:"1"`                     // 활성 세션 수
+}
+
+// DuplicateGroup 중복 후보 그룹
+type DuplicateGroup struct {
+	Reason string          `json:"reason" example:"same_student_id"` // same_student_id | similar_identity
+	Key    string          `json:"key" example:"2025320000"`
+	Users  []DuplicateUser `json:"users"`
+}
+
+// DuplicateReportResponse 중복 계정 리포트
+type DuplicateReportResponse struct {
+	BySameStudentID []DuplicateGroup `json:"by_student_id"`
+	BySimilarity    []DuplicateGroup `json:"by_similarity"`
+}
+
+// MergeUsersRequest 병합 요청
+type MergeUsersRequest struct {
+	SurvivorSerialID int64   `json:"survivor_serial_id" example:"123456789012"`
+	MergedSerialIDs  []int64 `json:"merged_serial_ids"`
+	DryRun           bool    `json:"dry_run" example:"true"`
+}
+
+// MergeUsersResponse 병합 결과 (dry_run이면 미리보기)
+type MergeUsersResponse struct {
+	DryRun           bool    `json:"dry_run" example:"true"`
+	SurvivorSerialID int64   `json:"survivor_serial_id" example:"123456789012"`
+	MergedSerialIDs  []int64 `json:"merged_serial_ids"`
+	MovedAssignments int64   `json:"moved_assignments" example:"2"`
+	MovedLockers     int64   `json:"moved_lockers" example:"1"`
+	MovedSessions    int64   `json:"moved_sessions" example:"3"`
+	DeletedUsers     int64   `json:"deleted_users" example:"1"`
+}
+
+// GetDuplicateUsers godoc
+// @Summary      중복 계정 후보 리포트 (관리자)
+// @Description  같은 학번을 가진 계정 그룹과, 학번/이름/전화번호 중 두 가지 이상이 한 글자 이내로 비슷한 계정 그룹을 반환합니다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Success      200 {object} DuplicateReportResponse
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/users/duplicates [get]
+func GetDuplicateUsers(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		users, err := loadDuplicateCandidates(c.Context(), d)
+		if err != nil {
+			log.Printf("GetDuplicateUsers: failed to load users: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		out := DuplicateReportResponse{
+			BySameStudentID: []DuplicateGroup{},
+			BySimilarity:    []DuplicateGroup{},
+		}
+
+		// 1) 같은 학번 그룹
+		byStudent := map[string][]DuplicateUser{}
+		for _, u := range users {
+			byStudent[u.StudentID] = append(byStudent[u.StudentID], u)
+		}
+		for sid, group := range byStudent {
+			if len(group) > 1 {
+				out.BySameStudentID = append(out.BySameStudentID, DuplicateGroup{Reason: "same_student_id", Key: sid, Users: group})
+			}
+		}
+		sort.Slice(out.BySameStudentID, func(i, j int) bool { return out.BySameStudentID[i].Key < out.BySameStudentID[j].Key })
+
+		// 2) 유사도 그룹: 학번이 다른 쌍 중 (학번, 이름, 전화번호) 세 필드 중 두 개 이상이 편집거리 1 이내
+		//    - 학생회 규모(수백~수천 명)에서는 O(n^2) 비교로 충분
+		//    - union-find로 연결된 쌍들을 하나의 그룹으로 묶는다
+		parent := make([]int, len(users))
+		for i := range parent {
+			parent[i] = i
+		}
+		var find func(int) int
+		find = func(i int) int {
+			for parent[i] != i {
+				parent[i] = parent[parent[i]]
+				i = parent[i]
+			}
+			return i
+		}
+		for i := 0; i < len(users); i++ {
+			for j := i + 1; j < len(users); j++ {
+				if users[i].StudentID == users[j].StudentID {
+					continue // 1)에서 이미 보고됨
+				}
+				if similarIdentity(users[i].User, users[j].User) {
+					parent[find(i)] = find(j)
+				}
+			}
+		}
+		groups := map[int][]DuplicateUser{}
+		for i := range users {
+			groups[find(i)] = append(groups[find(i)], users[i])
+		}
+		for _, group := range groups {
+			if len(group) > 1 {
+				out.BySimilarity = append(out.BySimilarity, DuplicateGroup{
+					Reason: "similar_identity",
+					Key:    fmt.Sprintf("%s/%s", group[0].StudentID, group[0].Name),
+					Users:  group,
+				})
+			}
+		}
+		sort.Slice(out.BySimilarity, func(i, j int) bool { return out.BySimilarity[i].Key < out.BySimilarity[j].Key })
+
+		return c.JSON(out)
+	}
+}
+
+// MergeUsers godoc
+// @Summary      중복 계정 병합 (관리자)
+// @Description  merged_serial_ids 계정의 사물함 배정 히스토리, 사물함 소유권, 세션을 survivor_serial_id 계정으로 한 트랜잭션에서 옮기고 병합된 계정을 삭제합니다. 병합될 계정 중 선점(hold) 중인 계정이 있으면 409를 반환합니다(선점을 해제하거나 만료된 뒤 다시 시도). dry_run=true면 실제 반영 없이 결과만 미리 봅니다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        payload body MergeUsersRequest true "병합 요청"
+// @Success      200 {object} MergeUsersResponse
+// @Failure      400 {object} ErrorResponse "invalid merge request"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      404 {object} ErrorResponse "user not found"
+// @Failure      409 {object} ErrorResponse "more than one active locker/hold among merged users / merged user is holding a locker"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/users/merge [post]
+func MergeUsers(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req MergeUsersRequest
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.ErrBadRequest
+		}
+		if req.SurvivorSerialID == 0 || len(req.MergedSerialIDs) == 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "survivor_serial_id and merged_serial_ids are required")
+		}
+		seen := map[int64]bool{}
+		for _, id := range req.MergedSerialIDs {
+			if id == req.SurvivorSerialID || seen[id] {
+				return fiber.NewError(fiber.StatusBadRequest, "merged_serial_ids must be distinct and must not contain survivor_serial_id")
+			}
+			seen[id] = true
+		}
+
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		// 1) 관련 사용자 행 잠금 + 존재 확인
+		all := append([]int64{req.SurvivorSerialID}, req.MergedSerialIDs...)
+		var (
+			locked            int
+			survivorStudentID string
+		)
+		rows, err := tx.Query(c.Context(),
+			`SELECT serial_id, student_id FROM users
+			  WHERE serial_id = ANY($1) AND deleted_at IS NULL
+			  ORDER BY serial_id
+			  FOR UPDATE`,
+			all)
+		if err != nil {
+			log.Printf("MergeUsers: failed to lock users: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		for rows.Next() {
+			var (
+				sid       int64
+				studentID string
+			)
+			if err := rows.Scan(&sid, &studentID); err != nil {
+				rows.Close()
+				return fiber.ErrInternalServerError
+			}
+			if sid == req.SurvivorSerialID {
+				survivorStudentID = studentID
+			}
+			locked++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		if locked != len(all) {
+			return fiber.NewError(fiber.StatusNotFound, "user not found")
+		}
+
+		// 2) 충돌 검사: 사용자당 활성 배정(hold/confirmed)은 1건만 허용 (ux_active_assignment_per_user)
+		// - 병합될 계정의 hold는 옮기지 않는다: Redis hold 키(locker:hold:{id}, user:hold:{serial})에 옛 serial_id가 남아
+		//   survivor가 연장/해제하지 못하고 TTL까지 사물함이 묶이므로, RekeyLegacySerials처럼 hold가 끝난 뒤 처리
+		var active, mergedHolds int
+		if err := tx.QueryRow(c.Context(),
+			`SELECT COUNT(*),
+			        COUNT(*) FILTER (WHERE state = 'hold' AND user_serial_id = ANY($2))
+			   FROM locker_assignments
+			  WHERE user_serial_id = ANY($1) AND state IN ('hold', 'confirmed')`,
+			all, req.MergedSerialIDs).Scan(&active, &mergedHolds); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		if active > 1 {
+			return fiber.NewError(fiber.StatusConflict, "more than one active locker/hold among merged users; release extras first")
+		}
+		if mergedHolds > 0 {
+			return fiber.NewError(fiber.StatusConflict, "a merged user is holding a locker; retry after the hold is confirmed, released or expired")
+		}
+
+		out := MergeUsersResponse{
+			DryRun:           req.DryRun,
+			SurvivorSerialID: req.SurvivorSerialID,
+			MergedSerialIDs:  req.MergedSerialIDs,
+		}
+
+		// 3) 배정 히스토리 이동
+		ct, err := tx.Exec(c.Context(),
+			`UPDATE locker_assignments SET user_serial_id = $1 WHERE user_serial_id = ANY($2)`,
+			req.SurvivorSerialID, req.MergedSerialIDs)
+		if err != nil {
+			log.Printf("MergeUsers: failed to move assignments: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		out.MovedAssignments = ct.RowsAffected()
+
+		// 4) 사물함 소유권 이동 (owner_student_id도 survivor 기준으로 맞춤)
+		ct, err = tx.Exec(c.Context(),
+			`UPDATE locker_info SET owner_serial_id = $1, owner_student_id = $2 WHERE owner_serial_id = ANY($3)`,
+			req.SurvivorSerialID, survivorStudentID, req.MergedSerialIDs)
+		if err != nil {
+			log.Printf("MergeUsers: failed to move locker ownership: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		out.MovedLockers = ct.RowsAffected()
+
+		// 5) 세션 이동: 다음 refresh부터 survivor 계정으로 토큰이 발급된다.
+		//    기존 access token은 sub가 병합된 계정이므로 커밋 후 블랙리스트 처리.
+		rows, err = tx.Query(c.Context(),
+			`UPDATE auth_refresh_tokens SET user_serial_id = $1
+			  WHERE user_serial_id = ANY($2)
+			RETURNING access_jti`,
+			req.SurvivorSerialID, req.MergedSerialIDs)
+		if err != nil {
+			log.Printf("MergeUsers: failed to move sessions: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		var jtis []string
+		for rows.Next() {
+			var jti sql.NullString
+			if err := rows.Scan(&jti); err != nil {
+				rows.Close()
+				return fiber.ErrInternalServerError
+			}
+			out.MovedSessions++
+			if jti.Valid {
+				jtis = append(jtis, jti.String)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		// 6) 병합된 계정 삭제 (참조하는 행은 모두 옮겼으므로 CASCADE로 지워지는 것 없음)
+		ct, err = tx.Exec(c.Context(), `DELETE FROM users WHERE serial_id = ANY($1)`, req.MergedSerialIDs)
+		if err != nil {
+			log.Printf("MergeUsers: failed to delete merged users: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		out.DeletedUsers = ct.RowsAffected()
+
+		// dry_run이면 defer의 Rollback으로 전부 되돌린다.
+		if req.DryRun {
+			return c.JSON(out)
+		}
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.AdminUserMerge, audit.TargetUser, strconv.FormatInt(req.SurvivorSerialID, 10)).
+			Change(map[string]any{"merged_serial_ids": req.MergedSerialIDs}, out)); err != nil {
+			log.Printf("MergeUsers: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		for _, jti := range jtis {
+			blacklistAccessJTI(c.Context(), d.RDB, jti)
+		}
+		if out.MovedLockers > 0 {
+			d.Lockers.Publish(c.Context(), 0, lockercache.EventAdmin)
+		}
+		adminID, _ := c.Locals("user_serial_id").(int64)
+		log.Printf("MergeUsers: admin %d merged %v into %d (assignments=%d, lockers=%d, sessions=%d)",
+			adminID, req.MergedSerialIDs, req.SurvivorSerialID, out.MovedAssignments, out.MovedLockers, out.MovedSessions)
+
+		return c.JSON(out)
+	}
+}
+
+// ───────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ───────────────────────────────────────────────────────────────────────────────
+
+// loadDuplicateCandidates: 탈퇴하지 않은 전체 사용자 + 배정/세션 요약
+func loadDuplicateCandidates(ctx context.Context, d Deps) ([]DuplicateUser, error) {
+	rows, err := d.DB.Query(ctx,
+		`SELECT u.serial_id, u.student_id, u.name, u.phone_number,
+		        li.locker_id,
+		        (SELECT COUNT(*) FROM locker_assignments a WHERE a.user_serial_id = u.serial_id),
+		        (SELECT COUNT(*) FROM auth_refresh_tokens t
+		          WHERE t.user_serial_id = u.serial_id AND t.revoked_at IS NULL AND now() < t.expires_at)
+		   FROM users u
+		   LEFT JOIN locker_info li ON li.owner_serial_id = u.serial_id
+		  WHERE u.deleted_at IS NULL
+		  ORDER BY u.student_id, u.serial_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []DuplicateUser
+	for rows.Next() {
+		var (
+			u      DuplicateUser
+			locker sql.NullInt32
+		)
+		if err := rows.Scan(&u.SerialID, &u.StudentID, &u.Name, &u.PhoneNumber, &locker, &u.Assignments, &u.Sessions); err != nil {
+			return nil, err
+		}
+		if locker.Valid {
+			v := int(locker.Int32)
+			u.ActiveLockerID = &v
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// similarIdentity: 학번/이름/전화번호 중 두 필드 이상이 편집거리 1 이내면 같은 사람일 가능성이 높다고 본다.
+func similarIdentity(a, b User) bool {
+	matches := 0
+	if util.EditDistance(a.StudentID, b.StudentID) <= 1 {
+		matches++
+	}
+	if util.EditDistance(a.Name, b.Name) <= 1 {
+		matches++
+	}
+	if util.EditDistance(a.PhoneNumber, b.PhoneNumber) <= 1 {
+		matches++
+	}
+	return matches >= 2
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestAudit: 요청자(JWT의 serial_id, 관리자 여부)와 IP/User-Agent를 채운 감사 이벤트
+// - 대상/동작은 .On(...)으로, 변경 전후는 .Change(...)로 채운 뒤 변경과 같은 트랜잭션에서 audit.Record
+func requestAudit(c *fiber.Ctx) audit.Event {
+	serialID, _ := c.Locals("user_serial_id").(int64)
+	role := audit.RoleUser
+	if admin, _ := c.Locals("is_admin").(bool); admin {
+		role = audit.RoleAdmin
+	}
+	return audit.Event{
+		ActorSerialID: serialID,
+		ActorRole:     role,
+		IP:            clientIP(c),
+		UserAgent:     string(c.Request().Header.UserAgent()),
+	}
+}
@@ -2,15 +2,24 @@ package handlers
 
 import (
 	"crypto/sha256"
+	"database/sql"
 	"encoding/base64"
-	"encoding/binary"
-	"fmt" // 추가
 	"log"
 	"net"
 	"regexp"
+	"strconv"
 	"strings" // 추가
 	"time"
 
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/cache"
+	"github.com/KUCSEPotato/locker-server/internal/holdstore"
+	"github.com/KUCSEPotato/locker-server/internal/lifecycle"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/KUCSEPotato/locker-server/internal/reconcile"
+	"github.com/KUCSEPotato/locker-server/internal/round"
+	"github.com/KUCSEPotato/locker-server/internal/scheduler"
+	"github.com/KUCSEPotato/locker-server/internal/serial"
 	"github.com/KUCSEPotato/locker-server/internal/util"
 	"github.com/gofiber/fiber/v2"
 
@@ -23,8 +32,17 @@ import (
 // Deps: 핸들러들이 의존하는 리소스(DB, Redis 등)를 담는 구조체
 // - 의존성 주입(DI) 방식으로 테스트/확장성이 좋아짐
 type Deps struct {
-	DB  *pgxpool.Pool // PostgreSQL 풀
-	RDB *redis.Client // Redis 클라이언트(여기 파일에선 사용 안하지만 통일성 위해 포함)
+	DB    *pgxpool.Pool    // PostgreSQL 풀
+	RDB   *redis.Client    // Redis 클라이언트(여기 파일에선 사용 안하지만 통일성 위해 포함)
+	Holds *holdstore.Store // 사물함 hold 저장소 (Redis Lua 기반, 소유자 확인 해제/연장)
+
+	Reconciler *reconcile.Reconciler // Redis hold ↔ DB 정합성 검사기 (관리자 API)
+	Jobs       *scheduler.Runner     // 백그라운드 작업 실행기 (헬스 체크에서 상태 노출)
+	InFlight   *lifecycle.InFlight   // 종료 시 drain할 요청(hold/confirm 등) 추적
+
+	RedisHealth *cache.Monitor            // Redis 상태 감시 (degraded mode 판단)
+	Listener    *scheduler.ExpiryListener // Redis 만료 알림 리스너 (헬스 체크에서 상태 노출)
+	Lockers     *lockercache.Cache        // 사물함 목록 스냅샷 캐시 (상태 변경 시 Publish로 무효화)
 }
 
 // 요청, 응답 구조체 정의
@@ -52,8 +70,8 @@ type LoginResponse struct {
 
 // token
 type RefreshRequest struct {
-	AccessToken  string `json:"access_token"` // 선택적: 블랙리스트용
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token"`  // 선택적: 블랙리스트용
+	RefreshToken string `json:"refresh_token"` // 쿠키 모드에서는 생략 (refresh_token 쿠키 사용)
 }
 
 type LogoutRequest struct {
@@ -64,7 +82,7 @@ type LogoutRequest struct {
 // 취약점 주의: hardcoded-credentials Embedding credentials in source code risks unauthorized access
 type RefreshResponse struct {
 	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // 쿠키 모드에서는 HttpOnly 쿠키로만 전달
 }
 
 type LogoutResponse struct {
@@ -87,11 +105,12 @@ type LoginOrRegisterRequest struct {
 // LoginOrRegisterResponse is the response returned by LoginOrRegister handler
 type LoginOrRegisterResponse struct {
 	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // 쿠키 모드에서는 HttpOnly 쿠키로만 전달
 	SerialID     int    `json:"serial_id"`
 }
 
 /*
+-- (참고) 초기 설계 메모. serial_id 발급 방식은 internal/serial, 008 마이그레이션 참고
 -- users 테이블에 custom_serial 컬럼이 없다면 추가
 ALTER TABLE users
   ADD COLUMN IF NOT EXISTS custom_serial BIGINT;
@@ -118,9 +137,16 @@ CREATE INDEX IF NOT EXISTS ix_users_custom_serial ON users(custom_serial);
 // @Failure      400 {object} ErrorResponse "invalid phone_number format"
 // @Failure      400 {object} ErrorResponse "only numeric characters are allowed in phone_number"
 // @Failure      400 {object} ErrorResponse "invalid name length"
+// @Failure      403 {object} ErrorResponse "not eligible for this round (not_on_roster | roster_name_mismatch | fee_unpaid | student_id_prefix | admission_year): ..."
 // @Failure      500 {object} ErrorResponse "internal server error"
 // @Router       /auth/login-or-register [post]
 func LoginOrRegister(d Deps) fiber.Handler {
+	// serial_id 발급기 (USER_SERIAL_SECRET 필수 - JWT 시크릿처럼 서버 시작 시 검증)
+	serials, err := serial.NewAllocatorFromEnv()
+	if err != nil {
+		log.Fatalf("LoginOrRegister: %v", err)
+	}
+
 	return func(c *fiber.Ctx) error {
 		// 1) 요청 파싱
 		var req LoginOrRegisterRequest
@@ -138,83 +164,80 @@ func LoginOrRegister(d Deps) fiber.Handler {
 		if ok := regexp.MustCompile(`^\d{10}$`).MatchString(req.StudentID); !ok {
 			return fiber.NewError(fiber.StatusBadRequest, "invalid student_id format")
 		}
-		if len(req.Phone) < 10 || len(req.Phone) > 15 {
-			return fiber.NewError(fiber.StatusBadRequest, "invalid phone_number format")
-		}
-		if ok := regexp.MustCompile(`^\d+$`).MatchString(req.Phone); !ok {
-			return fiber.NewError(fiber.StatusBadRequest, "only numeric characters are allowed in phone_number")
+		if err := validatePhone(req.Phone); err != nil {
+			return err
 		}
-		if l := len([]rune(req.Name)); l < 2 || l > 20 {
-			return fiber.NewError(fiber.StatusBadRequest, "invalid name length")
+		if err := validateName(req.Name); err != nil {
+			return err
 		}
 
-		// 3) 커스텀 일련번호 생성 (학번+전화번호+salt → SHA256 → 12자리 숫자)
-		customSerial, err := generateCustomSerial(req.StudentID, req.Name, req.Phone)
+		// 2-1) 현재 회차 신청 자격 (명단/학번/회비 규칙) - 관리자 계정은 규칙과 상관없이 로그인 가능
+		rd, err := round.Current(c.Context(), d.DB)
 		if err != nil {
-			log.Printf("GenerateCustomSerial failed: %v", err)
 			return fiber.ErrInternalServerError
 		}
+		if _, err := checkEligibility(c, d, rd, req.StudentID, req.Name); err != nil {
+			var isAdmin bool
+			_ = d.DB.QueryRow(c.Context(),
+				`SELECT is_admin FROM users
+				  WHERE student_id = $1 AND name = $2 AND phone_number = $3 AND deleted_at IS NULL`,
+				req.StudentID, req.Name, req.Phone).Scan(&isAdmin)
+			if !isAdmin {
+				return err
+			}
+		}
 
-		// 4) 원자적 UPSERT: (student_id, name, phone_number) 유니크 기준
-		//    - 새 레코드면 201, 기존이면 200
+		// 3~4) serial_id 발급 + 원자적 UPSERT: (student_id, name, phone_number) 유니크 기준
+		//    - 새 레코드면 201, 기존이면 200 (기존 계정은 저장된 serial_id를 그대로 돌려받음)
+		//    - 후보 serial_id가 다른 계정과 충돌(users_pkey 위반)하면 다음 후보로 재시도
 		var (
 			serialID int64
 			inserted bool
 		)
-		err = d.DB.QueryRow(c.Context(), `
-			INSERT INTO users (student_id, name, phone_number, serial_id, created_at)
-			VALUES ($1, $2, $3, $4, now())
-			ON CONFLICT (student_id, name, phone_number)
-			DO UPDATE SET
-    		name = EXCLUDED.name,
-    		phone_number = EXCLUDED.phone_number,
-			updated_at = now()
-			RETURNING serial_id, (xmax = 0) AS inserted
-		`, req.StudentID, req.Name, req.Phone, customSerial).Scan(&serialID, &inserted)
+		err = serials.Allocate(req.StudentID, req.Name, req.Phone, func(candidate int64) error {
+			return d.DB.QueryRow(c.Context(), `
+				INSERT INTO users (student_id, name, phone_number, serial_id, serial_scheme, created_at)
+				VALUES ($1, $2, $3, $4, $5, now())
+				ON CONFLICT (student_id, name, phone_number)
+				DO UPDATE SET
+				name = EXCLUDED.name,
+				phone_number = EXCLUDED.phone_number,
+				updated_at = now()
+				RETURNING serial_id, (xmax = 0) AS inserted
+			`, req.StudentID, req.Name, req.Phone, candidate, serial.SchemeHMAC).Scan(&serialID, &inserted)
+		})
 		if err != nil {
 			log.Printf("LoginOrRegister: upsert users failed: %v", err)
 			return fiber.ErrInternalServerError
 		}
 
-		statusCode := fiber.StatusOK
+		statusCode, action := fiber.StatusOK, audit.AuthLogin
 		if inserted {
-			statusCode = fiber.StatusCreated
+			statusCode, action = fiber.StatusCreated, audit.AuthRegister
 			log.Printf("New user registered: student_id=%s, name=%s", req.StudentID, req.Name)
 		} else {
 			log.Printf("Existing user logged in: student_id=%s", req.StudentID)
 		}
 
-		// 5) Access/Refresh 토큰 발급
-		accessToken, err := util.IssueAccessToken(serialID, req.StudentID)
+		// 5) 세션(refresh) 생성 + Access 토큰 발급
+		accessToken, refreshPlain, err := issueSession(c, d, serialID, req.StudentID, action)
 		if err != nil {
-			log.Printf("LoginOrRegister: failed to issue access token for student_id=%s: %v", req.StudentID, err)
-			return fiber.ErrInternalServerError
-		}
-
-		refreshPlain := util.RandomToken(32)
-		refreshHash := sha256.Sum256([]byte(refreshPlain))
-		hashB64 := base64.RawURLEncoding.EncodeToString(refreshHash[:])
-
-		userAgent := string(c.Request().Header.UserAgent())
-		clientIPAddr := clientIP(c)
-		refreshExpires := time.Now().Add(time.Hour * time.Duration(util.EnvInt("JWT_REFRESH_TTL_H", 336)))
-
-		_, err = d.DB.Exec(c.Context(), `
-			INSERT INTO auth_refresh_tokens (user_serial_id, token_hash, expires_at, user_agent, ip)
-			VALUES ($1, $2, $3, $4, $5)
-			ON CONFLICT (token_hash) DO NOTHING
-		`, serialID, hashB64, refreshExpires, userAgent, clientIPAddr)
-		if err != nil {
-			log.Printf("LoginOrRegister: failed to store refresh token for user with serial_id=%d: %v", serialID, err)
+			log.Printf("LoginOrRegister: failed to issue tokens for user with serial_id=%d: %v", serialID, err)
 			return fiber.ErrInternalServerError
 		}
 
 		// 6) 응답
-		return c.Status(statusCode).JSON(LoginOrRegisterResponse{
+		//    - 쿠키 모드: refresh 토큰은 HttpOnly 쿠키로만 내려보내고 바디에서는 제외
+		resp := LoginOrRegisterResponse{
 			AccessToken:  accessToken,
 			RefreshToken: refreshPlain, // 평문은 이 한 번만 반환
 			SerialID:     int(serialID),
-		})
+		}
+		if util.CookieTransport() {
+			setAuthCookies(c, accessToken, refreshPlain)
+			resp.RefreshToken = ""
+		}
+		return c.Status(statusCode).JSON(resp)
 	}
 }
 
@@ -222,26 +245,23 @@ func LoginOrRegister(d Deps) fiber.Handler {
 // Helpers
 // ───────────────────────────────────────────────────────────────────────────────
 
-// generateCustomSerial
-// 학번 + 전화번호 + "ku_info" 를 입력으로 SHA256 해시 → 상위 8바이트를 숫자로 변환 → 12자리로 축소(모듈러)
-func generateCustomSerial(studentID, name, phone string) (int64, error) {
-	id := strings.TrimSpace(studentID)
-	nm := strings.TrimSpace(name)
-	pn := strings.TrimSpace(phone)
-	if id == "" || nm == "" || pn == "" {
-		return 0, fmt.Errorf("invalid studentID or name or phone")
+// validatePhone: 전화번호 형식 검증 (10~15자리 숫자) - 로그인/프로필 수정 공용
+func validatePhone(phone string) error {
+	if len(phone) < 10 || len(phone) > 15 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid phone_number format")
 	}
-	const salt = "ku_info"
-	input := id + nm + pn + salt
-	sum := sha256.Sum256([]byte(input))
-
-	// 앞 8바이트 → uint64
-	u := binary.BigEndian.Uint64(sum[:8])
+	if ok := regexp.MustCompile(`^\d+$`).MatchString(phone); !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "only numeric characters are allowed in phone_number")
+	}
+	return nil
+}
 
-	// 12자리 숫자로 축소 (0 ~ 999,999,999,999)
-	const base uint64 = 1_000_000_000_000
-	num := u % base
-	return int64(num), nil
+// validateName: 이름 길이 검증 (2~20자, rune 기준) - 로그인/프로필 수정 공용
+func validateName(name string) error {
+	if l := len([]rune(name)); l < 2 || l > 20 {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid name length")
+	}
+	return nil
 }
 
 /*
@@ -424,10 +444,15 @@ func Login(d Deps) fiber.Handler {
 // @Router       /auth/refresh [post]
 func Refresh(d Deps) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// 1) 요청 바디(JSON) 파싱
+		// 1) 요청 바디(JSON) 파싱 (쿠키 모드에서는 바디가 비어 있을 수 있음)
 		var req RefreshRequest
-		if err := c.BodyParser(&req); err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "invalid request payload")
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid request payload")
+			}
+		}
+		if req.RefreshToken == "" && util.CookieTransport() {
+			req.RefreshToken = c.Cookies(util.RefreshTokenCookie)
 		}
 		if req.RefreshToken == "" {
 			return fiber.NewError(fiber.StatusBadRequest, "missing refresh_token")
@@ -443,6 +468,8 @@ func Refresh(d Deps) fiber.Handler {
 			authHeader := c.Get("Authorization")
 			if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
 				currentAccessToken = authHeader[7:]
+			} else if util.CookieTransport() {
+				currentAccessToken = c.Cookies(util.AccessTokenCookie)
 			}
 		}
 
@@ -450,15 +477,44 @@ func Refresh(d Deps) fiber.Handler {
 		hash := sha256.Sum256([]byte(req.RefreshToken))
 		hashB64 := base64.RawURLEncoding.EncodeToString(hash[:])
 
-		// 3) DB에서 유효한 리프레시인지 확인 (만료/회수 여부)
-		var sid int64 // user_serial_id
-		err := d.DB.QueryRow(c.Context(),
-			`SELECT user_serial_id FROM auth_refresh_tokens
-			  WHERE token_hash=$1
+		// 3) 새 Refresh 토큰 준비 (1회용이므로 매번 새로 발급)
+		refreshPlain := util.RandomToken(32)           // 안전한 랜덤 바이트 → base64
+		newHash := sha256.Sum256([]byte(refreshPlain)) // SHA-256 해시(더 강한 KDF도 가능)
+		newHashB64 := base64.RawURLEncoding.EncodeToString(newHash[:])
+
+		// user agent / ip는 감사성(어디서 발급됐는지 추적)
+		ua := string(c.Request().Header.UserAgent())
+		ip := clientIP(c)
+
+		// 만료 시각: 환경변수에서 시간(시간 단위)로 읽어와 now() + TTL
+		expires := time.Now().Add(time.Hour * time.Duration(util.EnvInt("JWT_REFRESH_TTL_H", 336)))
+
+		// 4) 유효한 리프레시인지 확인하면서 같은 세션 행의 토큰을 교체(rotate)
+		//    - access_jti는 이 문장에서 바꾸지 않으므로 RETURNING 값은 "이전" jti
+		//    - 보안적 측면에서 Refresh 토큰은 1회용: 기존 해시는 더 이상 매칭되지 않음
+		//    - 세션 ID(id)는 유지되므로 세션 목록/revoke가 refresh 이후에도 그대로 동작
+		//    - 한 문장으로 처리하므로 같은 refresh 토큰의 동시 사용 시 한쪽만 성공
+		var (
+			sid       int64          // auth_refresh_tokens.id
+			serialID  int64          // user_serial_id
+			oldJTI    sql.NullString // 이 세션으로 마지막에 발급된 access token의 jti
+			studentID string
+		)
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		err = tx.QueryRow(c.Context(),
+			`UPDATE auth_refresh_tokens
+			    SET token_hash = $2, expires_at = $3, user_agent = $4, ip = $5, last_used_at = now()
+			  WHERE token_hash = $1
 			    AND revoked_at IS NULL
-			    AND now() < expires_at`,
-			hashB64,
-		).Scan(&sid)
+			    AND now() < expires_at
+			RETURNING id, user_serial_id, access_jti`,
+			hashB64, newHashB64, expires, ua, ip,
+		).Scan(&sid, &serialID, &oldJTI)
 		if err != nil {
 			// 토큰이 없거나 만료/회수된 경우
 			if err == pgx.ErrNoRows {
@@ -467,68 +523,52 @@ func Refresh(d Deps) fiber.Handler {
 			return fiber.ErrInternalServerError
 		}
 
-		// 3.2) serial_id로 student_id 조회
-		var studentID string
-		err = d.DB.QueryRow(c.Context(), `SELECT student_id FROM users WHERE serial_id = $1`, sid).Scan(&studentID)
+		// 4.2) serial_id로 student_id 조회
+		err = tx.QueryRow(c.Context(), `SELECT student_id FROM users WHERE serial_id = $1`, serialID).Scan(&studentID)
 		if err != nil {
-			log.Printf("Refresh: could not find user with serial_id %d: %v", sid, err)
+			log.Printf("Refresh: could not find user with serial_id %d: %v", serialID, err)
 			return fiber.ErrUnauthorized
 		}
 
-		// 보안적 측면에서 Refresh 토큰은 1회용으로 설계하는 것이 좋음.
-		// 즉, Refresh 시 기존 토큰은 회수(revoke)하고 새 토큰을 발급.
-		_, err = d.DB.Exec(c.Context(),
-			`UPDATE auth_refresh_tokens
-			 SET revoked_at = now()
-			 WHERE token_hash = $1`,
-			hashB64,
-		)
+		// 5) 새 Access 발급 (같은 세션 ID로) + 세션에 jti 기록 + 감사 로그
+		token, jti, err := util.IssueAccessToken(serialID, studentID, sid)
 		if err != nil {
 			return fiber.ErrInternalServerError
 		}
+		if _, err := tx.Exec(c.Context(),
+			`UPDATE auth_refresh_tokens SET access_jti = $1 WHERE id = $2`, jti, sid,
+		); err != nil {
+			log.Printf("Refresh: failed to record access jti for session %d: %v", sid, err)
+			return fiber.ErrInternalServerError
+		}
+		ev := requestAudit(c)
+		ev.ActorSerialID, ev.ActorRole = serialID, audit.RoleUser // refresh 요청은 access token 없이 올 수 있다
+		if err := audit.Record(c.Context(), tx, ev.On(audit.AuthRefresh, audit.TargetSession, strconv.FormatInt(sid, 10)).
+			Change(nil, map[string]any{"expires_at": expires})); err != nil {
+			log.Printf("Refresh: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
 
-		// 3.5) 이전 access token을 블랙리스트에 추가 (있다면)
+		// 5.5) 이전 access token을 블랙리스트에 추가 (교체가 커밋된 뒤)
+		//      - 요청에 실려온 토큰 + 세션에 기록된 마지막 jti 둘 다 처리
 		if currentAccessToken != "" {
 			if jti, err := util.ExtractJTI(currentAccessToken); err == nil {
-				// Redis에 JTI를 블랙리스트로 저장 (TTL은 access token의 만료 시간까지)
-				ttlMin := util.EnvInt("JWT_ACCESS_TTL_MIN", 10)
-				blacklistKey := "blacklist:" + jti
-				_, _ = d.RDB.Set(c.Context(), blacklistKey, "revoked", time.Duration(ttlMin)*time.Minute).Result()
+				blacklistAccessJTI(c.Context(), d.RDB, jti)
 				log.Printf("Added access token to blacklist: %s", jti)
 			}
 		}
-
-		// 4) 새 Access 발급
-		token, err := util.IssueAccessToken(sid, studentID)
-		if err != nil {
-			return fiber.ErrInternalServerError
+		if oldJTI.Valid && oldJTI.String != "" {
+			blacklistAccessJTI(c.Context(), d.RDB, oldJTI.String)
 		}
 
-		// 5) 새 Refresh 토큰 발급 (1회용이므로 새로 발급)
-		refreshPlain := util.RandomToken(32)           // 안전한 랜덤 바이트 → base64
-		newHash := sha256.Sum256([]byte(refreshPlain)) // SHA-256 해시(더 강한 KDF도 가능)
-		newHashB64 := base64.RawURLEncoding.EncodeToString(newHash[:])
-
-		// user agent / ip는 감사성(어디서 발급됐는지 추적)
-		ua := string(c.Request().Header.UserAgent())
-		ip := clientIP(c)
-
-		// 만료 시각: 환경변수에서 시간(시간 단위)로 읽어와 now() + TTL
-		expires := time.Now().Add(time.Hour * time.Duration(util.EnvInt("JWT_REFRESH_TTL_H", 336)))
-
-		// 새 refresh token을 DB에 저장
-		_, err = d.DB.Exec(c.Context(),
-			`INSERT INTO auth_refresh_tokens(user_serial_id, token_hash, expires_at, user_agent, ip)
-     		 VALUES ($1, $2, $3, $4, $5)
-     		 ON CONFLICT (token_hash) DO NOTHING`,
-			sid, newHashB64, expires, ua, ip,
-		)
-		if err != nil {
-			log.Printf("Refresh: failed to store new refresh token: %v", err)
-			return fiber.ErrInternalServerError
+		// 6) 클라이언트에 반환 (쿠키 모드에서는 refresh 토큰을 쿠키로만)
+		if util.CookieTransport() {
+			setAuthCookies(c, token, refreshPlain)
+			return c.JSON(RefreshResponse{AccessToken: token})
 		}
-
-		// 6) 클라이언트에 반환
 		return c.JSON(RefreshResponse{
 			AccessToken:  token,
 			RefreshToken: refreshPlain,
@@ -560,7 +600,7 @@ func GetMe(d Deps) fiber.Handler {
 		// DB에서 해당 사용자 정보 조회
 		var studentID, name, phone string
 		err := d.DB.QueryRow(c.Context(),
-			`SELECT student_id, name, phone_number FROM users WHERE serial_id = $1 LIMIT 1`,
+			`SELECT student_id, name, phone_number FROM users WHERE serial_id = $1 AND deleted_at IS NULL LIMIT 1`,
 			serialID,
 		).Scan(&studentID, &name, &phone)
 
@@ -616,6 +656,17 @@ func Logout(d Deps) fiber.Handler {
 		req.AccessToken = strings.TrimSpace(req.AccessToken)
 		req.RefreshToken = strings.TrimSpace(req.RefreshToken)
 
+		// 쿠키 모드: 바디에 없으면 쿠키에서 꺼내고, 결과와 무관하게 인증 쿠키는 지운다
+		if util.CookieTransport() {
+			if req.RefreshToken == "" {
+				req.RefreshToken = c.Cookies(util.RefreshTokenCookie)
+			}
+			if req.AccessToken == "" {
+				req.AccessToken = c.Cookies(util.AccessTokenCookie)
+			}
+			clearAuthCookies(c)
+		}
+
 		// 미들웨어로부터 인증된 사용자 확인
 		authenticatedSerialID, _ := c.Locals("user_serial_id").(int64)
 
@@ -651,17 +702,18 @@ func Logout(d Deps) fiber.Handler {
 			hash := sha256.Sum256([]byte(req.RefreshToken))
 			hashB64 := base64.RawURLEncoding.EncodeToString(hash[:])
 
-			tag, err := d.DB.Exec(c.Context(),
+			n, err := revokeSessions(c.Context(), d, requestAudit(c).On(audit.AuthLogout, "", ""),
 				`UPDATE auth_refresh_tokens
                  SET revoked_at = now()
-                 WHERE token_hash = $1 AND revoked_at IS NULL`,
+                 WHERE token_hash = $1 AND revoked_at IS NULL
+                 RETURNING id, access_jti`,
 				hashB64,
 			)
 			if err != nil {
 				log.Printf("Logout: failed to revoke refresh token by hash: %v", err)
 				return fiber.ErrInternalServerError
 			}
-			if tag.RowsAffected() > 0 {
+			if n > 0 {
 				_, _ = d.RDB.Del(c.Context(), "refresh_token:"+req.RefreshToken).Result()
 			}
 			return c.JSON(LogoutResponse{Message: "logged out successfully"})
@@ -669,17 +721,18 @@ func Logout(d Deps) fiber.Handler {
 
 		// 3) refresh token 미제공이면서 인증된 사용자가 있으면 해당 사용자의 모든 refresh 토큰 revoke
 		if authenticatedSerialID != 0 {
-			tag, err := d.DB.Exec(c.Context(),
+			n, err := revokeSessions(c.Context(), d, requestAudit(c).On(audit.AuthLogout, "", ""),
 				`UPDATE auth_refresh_tokens
                  SET revoked_at = now()
-                 WHERE user_serial_id = $1 AND revoked_at IS NULL`,
+                 WHERE user_serial_id = $1 AND revoked_at IS NULL
+                 RETURNING id, access_jti`,
 				authenticatedSerialID,
 			)
 			if err != nil {
 				log.Printf("Logout: failed to revoke refresh tokens for user %d: %v", authenticatedSerialID, err)
 				return fiber.ErrInternalServerError
 			}
-			log.Printf("Logout: revoked %d refresh tokens for user %d", tag.RowsAffected(), authenticatedSerialID)
+			log.Printf("Logout: revoked %d refresh tokens for user %d", n, authenticatedSerialID)
 			return c.JSON(LogoutResponse{Message: "logged out successfully"})
 		}
 
@@ -687,65 +740,9 @@ func Logout(d Deps) fiber.Handler {
 	}
 }
 
-/*
-// LogoutAll 핸들러: 해당 사용자의 모든 세션(모든 디바이스)을 무효화
-// LogoutAll godoc
-// @Summary      전체 로그아웃 (모든 디바이스)
-// @Description  현재 사용자의 모든 Refresh Token을 무효화하여 모든 디바이스에서 로그아웃합니다.
-// @Tags         auth
-// @Accept       json
-// @Produce      json
-// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
-// @Success      200 {object} LogoutResponse
-// @Failure      401 {object} ErrorResponse
-// @Router       /auth/logout-all [post]
-func LogoutAll(d Deps) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		// JWT 미들웨어에서 저장한 학번(sub) - 이 핸들러는 인증이 필요함
-		studentID, ok := c.Locals("student_id").(string)
-		if !ok || studentID == "" {
-			return fiber.ErrUnauthorized
-		}
-
-		// 1) 현재 Access Token을 블랙리스트에 추가
-		authHeader := c.Get("Authorization")
-		if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-			accessToken := authHeader[7:]
-			if jti, err := util.ExtractJTI(accessToken); err == nil {
-				ttlMin := util.EnvInt("JWT_ACCESS_TTL_MIN", 10)
-				blacklistKey := "blacklist:" + jti
-				_, err := d.RDB.Set(c.Context(), blacklistKey, "revoked", time.Duration(ttlMin)*time.Minute).Result()
-				if err != nil {
-					log.Printf("Failed to blacklist current access token: %v", err)
-				}
-			}
-		}
-
-		// 2) 해당 사용자의 모든 Refresh Token을 revoke
-		result, err := d.DB.Exec(c.Context(),
-			`UPDATE auth_refresh_tokens
-			 SET revoked_at = now()
-			 WHERE student_id = $1 AND revoked_at IS NULL`,
-			studentID,
-		)
-		if err != nil {
-			log.Printf("Failed to revoke all refresh tokens for user %s: %v", studentID, err)
-			return fiber.ErrInternalServerError
-		}
-
-		rowsAffected := result.RowsAffected()
-		log.Printf("Revoked %d refresh tokens for user %s (logout-all)", rowsAffected, studentID)
-
-		// 3) 성공 응답
-		return c.JSON(LogoutResponse{
-			Message: "logged out from all devices successfully",
-		})
-	}
-}
-*/
 /*
 [보안 팁]
 - Refresh 토큰은 탈취 시 심각한 위험 → https 쿠키(httponly/secure) 보관을 고려.
 - 로그에 토큰 평문을 남기지 않기. 에러 메시지도 토큰/전화번호 등 민감정보 포함 금지.
-- 다중 디바이스 로그아웃/전체 로그아웃: auth_refresh_tokens에서 student_id로 revoked_at 업데이트.
+- 다중 디바이스 로그아웃/전체 로그아웃: auth_refresh_tokens에서 user_serial_id로 revoked_at 업데이트 (sessions.go).
 */
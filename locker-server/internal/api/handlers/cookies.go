@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 쿠키 전송 모드(AUTH_TOKEN_TRANSPORT=cookie)에서 토큰/CSRF 쿠키를 내려주고 지우는 헬퍼
+// - refresh: HttpOnly + Secure + SameSite, Path=/api/v1/auth (refresh/logout에만 첨부됨)
+// - access : HttpOnly + Secure + SameSite, Path=/api (보호 API 전체에 첨부됨)
+// - csrf   : JS가 읽어 X-CSRF-Token 헤더로 다시 보내는 double-submit 토큰 (HttpOnly 아님)
+
+// setAuthCookies: 로그인/refresh 성공 시 토큰 쿠키 + 새 CSRF 토큰 쿠키 설정
+func setAuthCookies(c *fiber.Ctx, accessToken, refreshToken string) {
+	accessTTL := time.Duration(util.EnvInt("JWT_ACCESS_TTL_MIN", 10)) * time.Minute
+	refreshTTL := time.Duration(util.EnvInt("JWT_REFRESH_TTL_H", 336)) * time.Hour
+
+	c.Cookie(authCookie(util.AccessTokenCookie, accessToken, util.AccessCookiePath, accessTTL, true))
+	c.Cookie(authCookie(util.RefreshTokenCookie, refreshToken, util.RefreshCookiePath, refreshTTL, true))
+	// CSRF 토큰은 refresh 토큰과 수명을 맞춘다 (refresh 때마다 새로 발급)
+	c.Cookie(authCookie(util.CSRFTokenCookie, util.RandomToken(32), "/", refreshTTL, false))
+}
+
+// clearAuthCookies: 로그아웃 시 모든 인증 쿠키 만료 처리
+func clearAuthCookies(c *fiber.Ctx) {
+	for _, ck := range []struct{ name, path string }{
+		{util.AccessTokenCookie, util.AccessCookiePath},
+		{util.RefreshTokenCookie, util.RefreshCookiePath},
+		{util.CSRFTokenCookie, "/"},
+	} {
+		expired := authCookie(ck.name, "", ck.path, 0, ck.name != util.CSRFTokenCookie)
+		expired.MaxAge = -1
+		expired.Expires = time.Unix(0, 0)
+		c.Cookie(expired)
+	}
+}
+
+// authCookie: 공통 속성(Secure/SameSite/Domain)을 채운 쿠키 생성
+func authCookie(name, value, path string, ttl time.Duration, httpOnly bool) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   util.CookieDomain(),
+		MaxAge:   int(ttl.Seconds()),
+		Expires:  time.Now().Add(ttl),
+		Secure:   util.CookieSecure(),
+		HTTPOnly: httpOnly,
+		SameSite: util.CookieSameSite(),
+	}
+}
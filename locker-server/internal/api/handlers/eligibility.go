@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/KUCSEPotato/locker-server/internal/eligibility"
+	"github.com/KUCSEPotato/locker-server/internal/round"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 회차 신청 자격 검사 (로그인 / hold / claim 공용)
+// - 규칙과 예외는 internal/eligibility, 회차별 규칙과 우선 순위 그룹은 internal/round 참고
+
+// lookupStudent: 자격 규칙이나 우선 순위 그룹이 있을 때만 명단/예외 조회 (없으면 DB 조회 없이 학번만)
+func lookupStudent(c *fiber.Ctx, d Deps, rd *round.Round, studentID string) (eligibility.Student, error) {
+	if !rd.NeedsStudent() {
+		return eligibility.Student{ID: studentID}, nil
+	}
+	st, err := eligibility.Lookup(c.Context(), d.DB, rd.ID, studentID)
+	if err != nil {
+		log.Printf("lookupStudent: round %d, student %s: %v", rd.ID, studentID, err)
+		return eligibility.Student{}, fiber.ErrInternalServerError
+	}
+	return st, nil
+}
+
+// checkEligibility: 현재 회차 자격이 없으면 403 "not eligible for this round (<code>): <설명>"
+// - 조회한 학생 정보를 돌려주므로 우선 순위 그룹 판정(rd.ScheduleFor)에 그대로 쓴다.
+func checkEligibility(c *fiber.Ctx, d Deps, rd *round.Round, studentID, name string) (eligibility.Student, error) {
+	st, err := lookupStudent(c, d, rd, studentID)
+	if err != nil {
+		return st, err
+	}
+	if dec := eligibility.Decide(rd.Eligibility, st, name); !dec.Eligible {
+		return st, fiber.NewError(fiber.StatusForbidden, "not eligible for this round ("+dec.Reason+"): "+dec.Message)
+	}
+	return st, nil
+}
@@ -3,51 +3,160 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"log"
 	"time"
 
+	"github.com/KUCSEPotato/locker-server/internal/scheduler"
 	"github.com/gofiber/fiber/v2"
-	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/redis/go-redis/v9"
 )
 
-// HealthCheck 핸들러: 간단한 헬스 체크
-// HealthCheck godoc
-// @Summary      헬스 체크
-// @Description  서버 상태 확인 (DB, Redis 연결 상태 포함)
+// 헬스 체크
+// - /health/live : 프로세스가 살아 있는지만 확인 (의존성 확인 없음) → 오케스트레이터 재시작 판단용
+// - /health/ready: 의존성별 상태/지연 시간 보고 → 로드밸런서 트래픽 투입 판단용
+//   - ready    : 모두 정상 (200)
+//   - degraded : Redis 장애 또는 백그라운드 작업 이상. 조회 API는 동작하고 hold 계열만 503 (200)
+//   - not_ready: Postgres 장애 또는 종료(drain) 중 (503)
+// - 에러 원문은 로그에만 남기고 응답에는 "timeout"/"unreachable"만 노출한다.
+
+// 응답 상태 값
+const (
+	healthReady    = "ready"
+	healthDegraded = "degraded"
+	healthNotReady = "not_ready"
+
+	checkUp       = "up"
+	checkDown     = "down"
+	checkDisabled = "disabled"
+	checkStale    = "stale"
+)
+
+// LiveResponse 생존 확인 응답
+type LiveResponse struct {
+	Status string `json:"status" example:"alive"`
+}
+
+// DependencyCheck 의존성 하나의 상태
+type DependencyCheck struct {
+	Status    string `json:"status" example:"up"`
+	LatencyMs *int64 `json:"latency_ms,omitempty" example:"2"`
+	Error     string `json:"error,omitempty" example:"timeout"`
+}
+
+// SchedulerCheck 백그라운드 작업 상태
+type SchedulerCheck struct {
+	Status string `json:"status" example:"up"`
+	scheduler.RunnerStatus
+}
+
+// ReadyChecks 의존성별 상태
+type ReadyChecks struct {
+	Postgres       DependencyCheck `json:"postgres"`
+	Redis          DependencyCheck `json:"redis"`
+	ExpiryListener DependencyCheck `json:"expiry_listener"`
+	Scheduler      SchedulerCheck  `json:"scheduler"`
+}
+
+// ReadyResponse 준비 상태 보고
+type ReadyResponse struct {
+	Status    string      `json:"status" example:"ready"`
+	Draining  bool        `json:"draining"`
+	CheckedAt time.Time   `json:"checked_at"`
+	Checks    ReadyChecks `json:"checks"`
+}
+
+// HealthLive godoc
+// @Summary      생존 확인 (liveness)
+// @Description  프로세스가 요청을 처리할 수 있는지만 확인합니다. DB/Redis 상태와 무관하게 200을 반환합니다.
+// @Tags         health
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} LiveResponse
+// @Router       /health/live [get]
+func HealthLive() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(LiveResponse{Status: "alive"})
+	}
+}
+
+// HealthReady godoc
+// @Summary      준비 상태 확인 (readiness)
+// @Description  Postgres/Redis 지연 시간, Redis 만료 알림 리스너 동작 여부, 백그라운드 작업 마지막 실행 상태를 보고합니다. Redis 장애 시 degraded(200, 조회만 가능), Postgres 장애 또는 종료 중이면 not_ready(503)입니다.
 // @Tags         health
 // @Accept       json
 // @Produce      json
-// @Success      200 {object} map[string]string "서버가 정상입니다"
-// @Failure      500 {object} map[string]string "서버에 문제가 있습니다"
-// @Router       /health [get]
-func HealthCheck(db *pgxpool.Pool, rdb *redis.Client) fiber.Handler {
+// @Success      200 {object} ReadyResponse "ready 또는 degraded"
+// @Failure      503 {object} ReadyResponse "not_ready"
+// @Router       /health/ready [get]
+func HealthReady(d Deps) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		ctx, cancel := context.WithTimeout(c.Context(), 2*time.Second)
 		defer cancel()
 
-		// PostgreSQL 연결 상태 확인
-		if err := db.Ping(ctx); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status": "unhealthy",
-				"db":     "unreachable",
-				"error":  err.Error(),
-			})
+		out := ReadyResponse{
+			Status:    healthReady,
+			Draining:  d.InFlight.Draining(),
+			CheckedAt: time.Now(),
+		}
+
+		// PostgreSQL
+		began := time.Now()
+		err := d.DB.Ping(ctx)
+		out.Checks.Postgres = dependencyCheck("postgres", time.Since(began), err)
+		// Redis (cache.Monitor가 2초마다 확인한 마지막 결과 사용 → 장애 중에도 응답이 느려지지 않음)
+		latency, _, err := d.RedisHealth.Last()
+		out.Checks.Redis = dependencyCheck("redis", latency, err)
+
+		// Redis 만료 알림 리스너 (선택 기능)
+		switch {
+		case !d.Listener.Enabled():
+			out.Checks.ExpiryListener.Status = checkDisabled
+		case d.Listener.Alive():
+			out.Checks.ExpiryListener.Status = checkUp
+		default:
+			out.Checks.ExpiryListener.Status = checkDown
 		}
 
-		// Redis 연결 상태 확인
-		if err := rdb.Ping(ctx).Err(); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status": "unhealthy",
-				"redis":  "unreachable",
-				"error":  err.Error(),
-			})
+		// 백그라운드 작업 (리더만 실행하므로 리더일 때만 최근 실행 여부를 본다)
+		out.Checks.Scheduler = SchedulerCheck{Status: checkUp, RunnerStatus: d.Jobs.Status()}
+		if out.Checks.Scheduler.Leader {
+			for _, job := range out.Checks.Scheduler.Jobs {
+				interval := time.Duration(job.IntervalSec) * time.Second
+				if job.LastError != "" ||
+					(job.LastFinishedAt != nil && time.Since(*job.LastFinishedAt) > 3*interval) {
+					out.Checks.Scheduler.Status = checkStale
+				}
+			}
 		}
 
-		// 모든 체크 통과 시
-		return c.JSON(fiber.Map{
-			"status": "healthy",
-			"db":     "reachable",
-			"redis":  "reachable",
-		})
+		// 종합 판단
+		switch {
+		case out.Draining || out.Checks.Postgres.Status != checkUp:
+			out.Status = healthNotReady
+		case out.Checks.Redis.Status != checkUp ||
+			out.Checks.ExpiryListener.Status == checkDown ||
+			out.Checks.Scheduler.Status != checkUp:
+			out.Status = healthDegraded
+		}
+
+		if out.Status == healthNotReady {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(out)
+		}
+		return c.JSON(out)
+	}
+}
+
+// dependencyCheck: ping 결과를 지연 시간/상태로 변환 (에러 원문은 로그로만)
+func dependencyCheck(name string, elapsed time.Duration, err error) DependencyCheck {
+	latency := elapsed.Milliseconds()
+	if err == nil {
+		return DependencyCheck{Status: checkUp, LatencyMs: &latency}
+	}
+
+	log.Printf("HealthReady: %s check failed: %v", name, err)
+	reason := "unreachable"
+	if errors.Is(err, context.DeadlineExceeded) {
+		reason = "timeout"
 	}
+	return DependencyCheck{Status: checkDown, LatencyMs: &latency, Error: reason}
 }
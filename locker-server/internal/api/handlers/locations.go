@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/floormap"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/gofiber/fiber/v2"
+)
+
+// LayoutCell 배치도의 사물함 한 칸 (row/col은 1부터, row 1이 맨 위)
+type LayoutCell struct {
+	LockerID int    `json:"locker_id" example:"101"`
+	Row      int    `json:"row" example:"1"`
+	Col      int    `json:"col" example:"3"`
+	Status   string `json:"status" enums:"free,held,taken,out_of_service" example:"free"`
+}
+
+// LocationLayout 위치의 격자 배치도
+type LocationLayout struct {
+	Rows              int          `json:"rows" example:"4"`
+	Cols              int          `json:"cols" example:"15"`
+	Cells             []LayoutCell `json:"cells"`
+	UnplacedLockerIDs []int        `json:"unplaced_locker_ids"` // 이 위치에 있지만 칸이 지정되지 않은 사물함
+}
+
+// LocationSummary 위치별 사물함 수 요약
+type LocationSummary struct {
+	LocationID   int             `json:"location_id" example:"1"`
+	Name         string          `json:"name" example:"정보관 B1"`
+	Total        int             `json:"total" example:"60"`
+	Free         int             `json:"free" example:"12"`
+	Held         int             `json:"held" example:"3"`
+	Taken        int             `json:"taken" example:"45"`
+	OutOfService int             `json:"out_of_service" example:"0"`
+	Layout       *LocationLayout `json:"layout"` // 배치도가 없으면 null
+}
+
+// ListLocationsResponse 위치 목록 응답
+type ListLocationsResponse struct {
+	Locations []LocationSummary `json:"locations"`
+}
+
+// ListLocations: 위치별 전체/상태별 사물함 수 + 배치도
+// - ListLockers와 같은 스냅샷을 사용하므로 ETag도 같다 (둘 중 하나가 바뀌면 둘 다 바뀜)
+// ListLocations godoc
+// @Summary      위치 목록 조회
+// @Description  사물함 위치별로 전체 사물함 수와 상태별(free/held/taken/out_of_service) 수, 격자 배치도(칸별 상태 포함)를 반환합니다. If-None-Match가 현재 ETag와 같으면 304를 반환합니다.
+// @Tags         lockers
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        If-None-Match header string false "이전 응답의 ETag"
+// @Success      200 {object} ListLocationsResponse "위치별 요약"
+// @Success      304 "변경 없음"
+// @Failure      401 {object} ErrorResponse "인증 필요"
+// @Failure      500 {object} ErrorResponse "서버 오류"
+// @Router       /locations [get]
+func ListLocations(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		snap, err := locationSnapshot(c, d)
+		if snap == nil {
+			return err
+		}
+		return c.JSON(ListLocationsResponse{Locations: summarizeLocations(snap, time.Now())})
+	}
+}
+
+// GetLocation godoc
+// @Summary      위치 하나 조회
+// @Description  위치 하나의 상태별 사물함 수와 격자 배치도를 반환합니다.
+// @Tags         lockers
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        If-None-Match header string false "이전 응답의 ETag"
+// @Param        id path int true "위치 ID" example(1)
+// @Success      200 {object} LocationSummary
+// @Success      304 "변경 없음"
+// @Failure      400 {object} ErrorResponse "잘못된 위치 ID"
+// @Failure      401 {object} ErrorResponse "인증 필요"
+// @Failure      404 {object} ErrorResponse "location not found"
+// @Failure      500 {object} ErrorResponse "서버 오류"
+// @Router       /locations/{id} [get]
+func GetLocation(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		snap, err := locationSnapshot(c, d)
+		if snap == nil {
+			return err
+		}
+		for _, sum := range summarizeLocations(snap, time.Now()) {
+			if sum.LocationID == id {
+				return c.JSON(sum)
+			}
+		}
+		return fiber.NewError(fiber.StatusNotFound, "location not found")
+	}
+}
+
+// GetLocationMap godoc
+// @Summary      위치 배치도 SVG
+// @Description  위치의 격자 배치도를 SVG 이미지로 반환합니다. 각 칸은 사물함 번호와 현재 상태 색(사용 가능: 초록, 선점 중: 주황, 사용 중: 회색, 사용 중지: 빨강)으로 표시됩니다.
+// @Tags         lockers
+// @Produce      image/svg+xml
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        If-None-Match header string false "이전 응답의 ETag"
+// @Param        id path int true "위치 ID" example(1)
+// @Success      200 {string} string "SVG 문서"
+// @Success      304 "변경 없음"
+// @Failure      400 {object} ErrorResponse "잘못된 위치 ID"
+// @Failure      401 {object} ErrorResponse "인증 필요"
+// @Failure      404 {object} ErrorResponse "location not found / location has no layout"
+// @Failure      500 {object} ErrorResponse "서버 오류"
+// @Router       /locations/{id}/map.svg [get]
+func GetLocationMap(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		snap, err := locationSnapshot(c, d)
+		if snap == nil {
+			return err
+		}
+
+		for _, sum := range summarizeLocations(snap, time.Now()) {
+			if sum.LocationID != id {
+				continue
+			}
+			if sum.Layout == nil {
+				return fiber.NewError(fiber.StatusNotFound, "location has no layout")
+			}
+			m := floormap.Map{Name: sum.Name, Rows: sum.Layout.Rows, Cols: sum.Layout.Cols}
+			for _, cell := range sum.Layout.Cells {
+				m.Cells = append(m.Cells, floormap.Cell{
+					LockerID: cell.LockerID, Row: cell.Row, Col: cell.Col, Status: cell.Status,
+				})
+			}
+			c.Set(fiber.HeaderContentType, "image/svg+xml; charset=utf-8")
+			return c.Send(floormap.RenderSVG(m))
+		}
+		return fiber.NewError(fiber.StatusNotFound, "location not found")
+	}
+}
+
+// locationSnapshot: 스냅샷 조회 + ETag/304 처리 (위치 응답에는 소유자 정보가 없으므로 모든 사용자에게 같은 ETag)
+// - 응답이 이미 끝났으면(304/에러) snap=nil
+func locationSnapshot(c *fiber.Ctx, d Deps) (*lockercache.Snapshot, error) {
+	snap, err := d.Lockers.Get(c.Context())
+	if err != nil {
+		return nil, fiber.ErrInternalServerError
+	}
+	c.Set(fiber.HeaderETag, snap.ETag)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), snap.ETag) {
+		return nil, c.SendStatus(fiber.StatusNotModified)
+	}
+	return snap, nil
+}
+
+// summarizeLocations: 스냅샷에서 위치별 상태 수와 배치도 계산
+func summarizeLocations(snap *lockercache.Snapshot, now time.Time) []LocationSummary {
+	out := make([]LocationSummary, 0, len(snap.Locations))
+	index := map[int]int{}
+	for _, loc := range snap.Locations {
+		index[loc.LocationID] = len(out)
+		sum := LocationSummary{LocationID: loc.LocationID, Name: loc.Name}
+		if loc.GridRows != nil && loc.GridCols != nil {
+			sum.Layout = &LocationLayout{
+				Rows:              *loc.GridRows,
+				Cols:              *loc.GridCols,
+				Cells:             []LayoutCell{},
+				UnplacedLockerIDs: []int{},
+			}
+		}
+		out = append(out, sum)
+	}
+
+	for _, l := range snap.Lockers {
+		i, ok := index[l.LocationID]
+		if !ok {
+			continue
+		}
+		sum := &out[i]
+		status := l.Status(now)
+		sum.Total++
+		switch status {
+		case lockercache.StatusFree:
+			sum.Free++
+		case lockercache.StatusHeld:
+			sum.Held++
+		case lockercache.StatusTaken:
+			sum.Taken++
+		case lockercache.StatusOutOfService:
+			sum.OutOfService++
+		}
+
+		if sum.Layout == nil {
+			continue
+		}
+		if l.GridRow == nil || l.GridCol == nil {
+			sum.Layout.UnplacedLockerIDs = append(sum.Layout.UnplacedLockerIDs, l.LockerID)
+			continue
+		}
+		sum.Layout.Cells = append(sum.Layout.Cells, LayoutCell{
+			LockerID: l.LockerID, Row: *l.GridRow, Col: *l.GridCol, Status: status,
+		})
+	}
+	return out
+}
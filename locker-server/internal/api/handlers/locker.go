@@ -1,39 +1,30 @@
 package handlers
 
 import (
-	"database/sql"
-	"os"
+	"errors"
+	"log"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/KUCSEPotato/locker-server/internal/scheduler"
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/holdstore"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/KUCSEPotato/locker-server/internal/round"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
-var lockerApplicationStart time.Time
-var lockerApplicationStartErr error
-var lockerApplicationEnd time.Time
-var lockerApplicationEndErr error
-
-func init() {
-	startStr := os.Getenv("LOCKER_APPLICATION_START")
-	if startStr != "" {
-		lockerApplicationStart, lockerApplicationStartErr = time.Parse(time.RFC3339, startStr)
-	}
-
-	endStr := os.Getenv("LOCKER_APPLICATION_END")
-	if endStr != "" {
-		lockerApplicationEnd, lockerApplicationEndErr = time.Parse(time.RFC3339, endStr)
-	}
-}
-
 // Locker Response
 type LockerResponse struct {
-	LockerID      int     `json:"locker_id"`
-	LocationID    string  `json:"location_id"`
-	Owner         *string `json:"owner,omitempty"` // null 가능
-	OwnerSerialID *int64  `json:"owner_serial_id,omitempty"`
+	LockerID      int               `json:"locker_id" example:"101"`
+	LocationID    int               `json:"location_id" example:"1"`
+	LocationName  string            `json:"location_name" example:"정보관 B1"`
+	Status        string            `json:"status" enums:"free,held,taken,out_of_service" example:"taken"` // free: 비어 있음, held: 누군가 선점 중, taken: 확정됨, out_of_service: 사용 중지
+	Owner         *string           `json:"owner,omitempty"`                                               // 소유자 본인/관리자에게만 공개
+	OwnerSerialID *int64            `json:"owner_serial_id,omitempty"`                                     // 소유자 본인/관리자에게만 공개
+	Attributes    *LockerAttributes `json:"attributes,omitempty"`                                          // 목록 조회에서만 포함
 	// RemainingCount int     `json:"remaining_count"` // 사용 가능한 사물함 수
 }
 
@@ -41,20 +32,36 @@ type LockerResponse struct {
 type HoldSuccessResponse struct {
 	Message   string         `json:"message" example:"locker held successfully"`
 	Locker    LockerResponse `json:"locker"`
-	ExpiresIn string         `json:"expires_in" example:"1 minutes"`
+	ExpiresAt time.Time      `json:"expires_at" example:"2025-10-15T15:01:00+09:00"`
 }
 
 // Hold Fallback Response
 type HoldFallbackResponse struct {
-	Message   string `json:"message" example:"locker held successfully"`
-	LockerID  int    `json:"locker_id" example:"101"`
-	ExpiresIn string `json:"expires_in" example:"1 minutes"`
+	Message   string    `json:"message" example:"locker held successfully"`
+	LockerID  int       `json:"locker_id" example:"101"`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-10-15T15:01:00+09:00"`
+}
+
+// Hold Extend Response
+type HoldExtendResponse struct {
+	Message        string    `json:"message" example:"hold extended successfully"`
+	LockerID       int       `json:"locker_id" example:"101"`
+	ExpiresAt      time.Time `json:"expires_at" example:"2025-10-15T15:02:00+09:00"`
+	ExtensionsLeft int       `json:"extensions_left" example:"0"`
 }
 
 // List Lockers Response
 type ListLockersResponse struct {
 	Lockers        []LockerResponse `json:"lockers"`
-	AvailableCount int              `json:"available_count" example:"45"`
+	AvailableCount int              `json:"available_count" example:"45"` // 필터와 무관한 전체 free 사물함 수
+	NextCursor     *string          `json:"next_cursor" example:"120"`    // 다음 페이지 cursor (마지막 페이지이거나 limit/cursor 없이 전체 조회면 null)
+}
+
+// Claim Success Response
+type ClaimSuccessResponse struct {
+	Message     string    `json:"message" example:"locker claimed successfully"`
+	LockerID    int       `json:"locker_id" example:"101"`
+	ConfirmedAt time.Time `json:"confirmed_at" example:"2025-10-15T15:00:03+09:00"`
 }
 
 // Simple Success Response
@@ -67,161 +74,381 @@ type MyLockerResponse struct {
 	Locker *LockerResponse `json:"locker"`
 }
 
+// 신청 기간 밖 403 코드
+const (
+	WindowNotOpenYet = "not_open_yet"
+	WindowClosed     = "closed"
+)
+
+// Application Window Response (신청 기간 밖 403)
+// - error는 ErrorResponse와 같은 사람용 문구, 나머지는 프론트 카운트다운용 값
+type ApplicationWindowResponse struct {
+	Error      string     `json:"error" example:"아직 신청 기간이 아닙니다. 신청 시작: 2025-03-02 10:00:00"`
+	Code       string     `json:"code" enums:"not_open_yet,closed" example:"not_open_yet"`
+	OpensAt    *time.Time `json:"opens_at" example:"2025-03-02T10:00:00+09:00"`  // 호출자의 신청 시작 (우선 순위 그룹 반영, null이면 제한 없음)
+	ClosesAt   *time.Time `json:"closes_at" example:"2025-03-06T18:00:00+09:00"` // 신청 마감 (null이면 제한 없음)
+	Group      string     `json:"group,omitempty" example:"4학년"`                 // 적용된 우선 순위 그룹
+	ServerTime time.Time  `json:"server_time" example:"2025-03-02T09:59:58.512+09:00"`
+}
+
 // ListLockers: 사물함 목록 조회
-// - locker_info + locker_locations 조인하여 위치명까지 함께 반환
+// - locker_info + locker_locations 조인하여 위치(id, 이름)와 현재 상태(free/held/taken)를 함께 반환
+// - 소유자 정보(owner, owner_serial_id)는 본인 사물함이거나 관리자일 때만 포함 (visibility.go)
+// - lockercache 스냅샷을 사용 (상태 변경 이벤트로 무효화), ETag가 같으면 304
+// - 필터: location_id, status(쉼표로 여러 개), min_id/max_id (사물함 번호 범위, 포함), size/row(쉼표로 여러 개), near_outlet
+// - 정렬: sort (기본 locker_id, size/-size/row/-row/near_outlet). 같은 값끼리는 locker_id 순
+// - 페이지네이션: limit 또는 cursor를 보낼 때만. 정렬 순서대로 limit개씩, 응답의 next_cursor를 cursor로 넘기면 다음 페이지 (filter/sort는 같게 유지)
+// - 둘 다 없으면 조건에 맞는 사물함 전체를 반환하고 next_cursor는 null (기존 클라이언트 호환)
 // ListLockers godoc
 // @Summary      사물함 목록 조회
-// @Description  모든 사물함 목록과 사용 가능한 사물함 수를 반환합니다
+// @Description  사물함 목록(위치, 상태 포함)과 사용 가능한 사물함 수를 반환합니다. 소유자 정보(owner, owner_serial_id)는 본인 사물함이거나 관리자일 때만 포함됩니다. 위치/상태/번호 범위/속성(크기, 층, 콘센트)으로 거르고 속성으로 정렬할 수 있습니다. limit 또는 cursor를 보내면 cursor 페이지네이션하고(next_cursor로 다음 페이지), 둘 다 없으면 조건에 맞는 사물함 전체를 반환합니다(next_cursor는 null). 응답에 ETag가 포함되며, If-None-Match가 현재 ETag와 같으면 본문 없이 304를 반환합니다.
 // @Tags         lockers
 // @Accept       json
 // @Produce      json
 // @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        If-None-Match header string false "이전 응답의 ETag"
+// @Param        location_id query int false "위치 ID"
+// @Param        status query string false "상태 (free, held, taken, out_of_service - 쉼표로 여러 개)" example(free,held)
+// @Param        min_id query int false "사물함 번호 하한 (포함)"
+// @Param        max_id query int false "사물함 번호 상한 (포함)"
+// @Param        size query string false "크기 (small, medium, large - 쉼표로 여러 개)" example(medium,large)
+// @Param        row query string false "층 (top, middle, bottom - 쉼표로 여러 개)" example(middle)
+// @Param        near_outlet query bool false "콘센트 근처만 (true) / 아닌 것만 (false)"
+// @Param        sort query string false "정렬 (locker_id, size, -size, row, -row, near_outlet). 속성이 없는 사물함은 항상 뒤로" example(-size)
+// @Param        limit query int false "페이지 크기 (최대 500). 생략 시 전체 반환, cursor만 보내면 100" minimum(1) maximum(500)
+// @Param        cursor query string false "이전 응답의 next_cursor"
 // @Success      200 {object} ListLockersResponse "사물함 목록과 사용 가능 수"
+// @Success      304 "변경 없음"
+// @Failure      400 {object} ErrorResponse "잘못된 필터/페이지 파라미터"
 // @Failure      401 {object} ErrorResponse "인증 필요"
 // @Failure      500 {object} ErrorResponse "서버 오류"
 // @Router       /lockers [get]
 func ListLockers(d Deps) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		rows, err := d.DB.Query(c.Context(),
-			`SELECT l.locker_id, l.owner_student_id, l.owner_serial_id, ll.name
-               FROM locker_info l
-               JOIN locker_locations ll ON ll.location_id = l.location_id
-               ORDER BY l.locker_id`)
+		f, err := parseLockerFilter(c)
+		if err != nil {
+			return err
+		}
+
+		snap, err := d.Lockers.Get(c.Context())
 		if err != nil {
 			return fiber.ErrInternalServerError
 		}
-		defer rows.Close()
 
-		var out []LockerResponse
-		for rows.Next() {
-			var it LockerResponse
-			var ownerSerial sql.NullInt64
-			if err := rows.Scan(&it.LockerID, &it.Owner, &ownerSerial, &it.LocationID); err != nil {
-				return fiber.ErrInternalServerError
+		// 소유자 정보 공개 범위에 따라 본문이 사용자마다 다르므로 ETag도 사용자별, 공유 캐시 금지
+		v := viewerFrom(c)
+		etag := v.etag(snap.ETag)
+		c.Set(fiber.HeaderETag, etag)
+		c.Set(fiber.HeaderCacheControl, "private, no-cache")
+		c.Vary(fiber.HeaderAuthorization, fiber.HeaderCookie)
+		if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+
+		now := time.Now()
+		out := ListLockersResponse{Lockers: []LockerResponse{}}
+		var matched []lockercache.Locker
+		statuses := map[int]string{}
+		for _, l := range snap.Lockers {
+			status := l.Status(now)
+			if status == lockercache.StatusFree {
+				out.AvailableCount++
 			}
-			if ownerSerial.Valid {
-				v := ownerSerial.Int64
-				it.OwnerSerialID = &v
-			} else {
-				it.OwnerSerialID = nil
+			if f.match(l, status) {
+				matched = append(matched, l)
+				statuses[l.LockerID] = status
 			}
-			out = append(out, it)
 		}
+		f.sortLockers(matched)
 
-		// 단일 응답에 사용 가능한 사물함 수 포함
-		var availableCount int
-		err = d.DB.QueryRow(c.Context(),
-			`SELECT COUNT(*) FROM locker_info WHERE owner_serial_id IS NULL`).Scan(&availableCount)
-		if err != nil {
-			return fiber.ErrInternalServerError
+		var last lockercache.Locker
+		for _, l := range matched {
+			if !f.afterCursor(l) {
+				continue
+			}
+			// 페이지가 찼는데 조건에 맞는 사물함이 더 있으면 다음 페이지가 있다는 뜻
+			if f.limit > 0 && len(out.Lockers) == f.limit {
+				next := f.cursorOf(last)
+				out.NextCursor = &next
+				break
+			}
+			out.Lockers = append(out.Lockers, v.apply(LockerResponse{
+				LockerID:      l.LockerID,
+				LocationID:    l.LocationID,
+				LocationName:  l.LocationName,
+				Status:        statuses[l.LockerID],
+				Owner:         l.OwnerStudentID,
+				OwnerSerialID: l.OwnerSerialID,
+				Attributes:    attributesOf(l),
+			}))
+			last = l
+		}
+		return c.JSON(out)
+	}
+}
+
+// etagMatches: If-None-Match 헤더(쉼표 목록, 약한 비교 W/, *)가 etag와 일치하는지
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
 		}
-		return c.JSON(ListLockersResponse{
-			Lockers:        out,
-			AvailableCount: availableCount,
-		})
 	}
+	return false
 }
 
 // HoldLocker: 사물함 "선점"
-// 1) Redis SETNX(key, student, TTL=1분) → 성공 시 첫 클릭 인정
+// 1) holdstore.Acquire(Lua): 사물함 키 + 사용자 키를 한 번에 설정 → 성공 시 첫 클릭 인정 (사용자당 hold 1개)
 // 2) DB에 locker_assignments(state='hold') 기록 (부분 유니크 인덱스로 중복 방지)
 // - 실패 케이스: 이미 hold/confirmed가 존재 → 409
 // HoldLocker godoc
 // @Summary      사물함 선점
-// @Description  특정 사물함을 선점합니다 (1분간 예약). Redis와 DB를 통해 동시성 제어를 하며, 성공 시 사물함 정보를 반환합니다. 신청 기간 외에는 접근이 불가능합니다.
+// @Description  특정 사물함을 선점합니다 (현재 회차의 hold TTL 동안 예약, 기본 1분). Redis와 DB를 통해 동시성 제어를 하며, 성공 시 사물함 정보를 반환합니다. 신청 기간 외에는 접근이 불가능합니다.
 // @Tags         lockers
 // @Accept       json
 // @Produce      json
 // @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        Idempotency-Key header string false "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생"
 // @Param        id path int true "사물함 ID (1-999 범위)" minimum(1) maximum(999) example(101)
 // @Success      201 {object} HoldSuccessResponse "선점 성공 - 사물함 정보 포함"
 // @Failure      400 {object} ErrorResponse "잘못된 요청 - 유효하지 않은 사물함 ID"
 // @Failure      401 {object} ErrorResponse "인증 필요 - JWT 토큰이 없거나 유효하지 않음"
-// @Failure      403 {object} ErrorResponse "신청 기간 외 - 신청 시작 전이거나 마감 후"
-// @Failure      409 {object} ErrorResponse "이미 선점됨 - 다른 사용자가 이미 선점했거나 본인이 이미 선점한 상태"
+// @Failure      403 {object} ApplicationWindowResponse "신청 기간 외 - 신청 시작 전(code=not_open_yet, 우선 순위 그룹이면 그룹 시작 시각)이거나 마감 후(code=closed). 회차 신청 자격이 없으면 ErrorResponse (not eligible for this round)"
+// @Failure      409 {object} ErrorResponse "이미 선점됨 - 다른 사용자가 이미 선점했거나 본인이 이미 선점한 상태, 또는 본인이 다른 사물함을 선점 중, 또는 사용 중지된 사물함"
 // @Failure      503 {object} ErrorResponse "서비스 일시 불가 - Redis 서버 장애"
+// @Failure      422 {object} ErrorResponse "같은 Idempotency-Key를 다른 요청에 재사용"
 // @Router       /lockers/{id}/hold [post]
 func HoldLocker(d Deps) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// 신청 시작 시간 체크
-		now := time.Now()
-		if lockerApplicationStartErr == nil && now.Before(lockerApplicationStart) {
-			return fiber.NewError(fiber.StatusForbidden, "아직 신청 기간이 아닙니다. 신청 시작: "+lockerApplicationStart.Format("2025-10-15 15:04:05"))
-		}
-
-		// 신청 마감 시간 체크
-		if lockerApplicationEndErr == nil && now.After(lockerApplicationEnd) {
-			return fiber.NewError(fiber.StatusForbidden, "신청 기간이 마감되었습니다. 신청 마감: "+lockerApplicationEnd.Format("2025-10-15 15:04:05"))
-		}
-
-		// URL 파라미터에서 locker id 추출
-		id, err := strconv.Atoi(c.Params("id"))
+		// 현재 회차 설정 (신청 기간 + hold TTL)
+		rd, err := round.Current(c.Context(), d.DB)
 		if err != nil {
-			return fiber.ErrBadRequest
+			return fiber.ErrInternalServerError
 		}
 
-		// 해당 locker의 만료된 hold를 먼저 정리
-		scheduler.CheckAndCleanupExpiredHold(d.DB, d.RDB, id)
-
 		// JWT 미들웨어에서 저장한 serial_id
 		serialID, _ := c.Locals("user_serial_id").(int64)
 		if serialID == 0 {
 			return fiber.ErrUnauthorized
 		}
 
-		// Redis 키: "locker:hold:{id}"
-		key := "locker:hold:" + strconv.Itoa(id)
+		// 회차 신청 자격 (명단/학번/회비 규칙, 관리자 예외)
+		studentID, _ := c.Locals("student_id").(string)
+		st, err := checkEligibility(c, d, rd, studentID, "")
+		if err != nil {
+			return err
+		}
+
+		// 신청 기간 체크 (우선 순위 그룹이면 그룹 시작 시각 기준)
+		if werr := checkApplicationWindow(rd.ScheduleFor(st), time.Now()); werr != nil {
+			return c.Status(fiber.StatusForbidden).JSON(werr)
+		}
 
-		// SETNX: 키가 없을 때만 set + TTL(1분). true=성공(첫 클릭), false=이미 누군가 보유중
-		// [250908] 1분으로 변경 테스트
-		ok, err := d.RDB.SetNX(c.Context(), key, serialID, 1*time.Minute).Result()
+		// URL 파라미터에서 locker id 추출
+		id, err := strconv.Atoi(c.Params("id"))
 		if err != nil {
-			// Redis 장애 → 503(Service Unavailable)
-			return fiber.ErrServiceUnavailable
+			return fiber.ErrBadRequest
 		}
-		if !ok {
-			// 이미 다른 사람이 hold했거나, 본인이 선점했을 수도 있음 → 409
-			return fiber.NewError(fiber.StatusConflict, "Locker already held by someone")
+
+		// 사용 중지(고장 등) 사물함은 선점 불가
+		if err := checkInService(c, d, id); err != nil {
+			return err
+		}
+
+		// Redis hold 획득 (locker:hold:{id} + user:hold:{serial}, TTL = 회차 hold TTL)
+		if err := d.Holds.Acquire(c.Context(), id, serialID, rd.HoldTTL); err != nil {
+			switch {
+			case errors.Is(err, holdstore.ErrLockerHeld):
+				// 이미 다른 사람이 hold했거나, 본인이 선점했을 수도 있음 → 409
+				return fiber.NewError(fiber.StatusConflict, "Locker already held by someone")
+			case errors.Is(err, holdstore.ErrUserHolding):
+				// 본인이 다른 사물함을 이미 hold 중 → 409
+				return fiber.NewError(fiber.StatusConflict, "You already hold another locker")
+			default:
+				// Redis 장애 → 503(Service Unavailable)
+				return fiber.NewError(fiber.StatusServiceUnavailable, "locker holds are temporarily unavailable (redis error), please retry shortly")
+			}
 		}
 
-		// DB 히스토리 기록 (hold)
+		// DB 히스토리 기록 (hold) + 감사 로그 (같은 트랜잭션)
 		// * 유니크 인덱스가 마지막 안전망(한 locker/한 user당 활성 1건)
-		_, err = d.DB.Exec(c.Context(),
-			`INSERT INTO locker_assignments(locker_id, user_serial_id, state, hold_expires_at)
-			 VALUES ($1,$2,'hold', now() + interval '1 minutes')`,
-			id, serialID)
+		// * 만료 시각은 Redis와 같은 TTL로 DB에서 계산 (timestamp 컬럼이라 timestamptz로 읽음)
+		expiresAt, err := recordHold(c, d, rd, id, serialID)
 		if err != nil {
-			// DB에서 막히면 Redis 키를 삭제(베스트 에포트)
-			_, _ = d.RDB.Del(c.Context(), key).Result()
+			log.Printf("HoldLocker: locker %d, user %d: %v", id, serialID, err)
+			// DB에서 막히면 방금 잡은 내 hold만 해제(베스트 에포트)
+			_ = d.Holds.Release(c.Context(), id, serialID)
 			return fiber.NewError(fiber.StatusConflict, "Locker hold failed on DB. Deleting Redis key.")
 		}
+		d.Lockers.Publish(c.Context(), id, lockercache.EventHold)
 
 		// 성공 시 사물함 정보도 함께 반환
-		var lockerInfo LockerResponse
+		lockerInfo := LockerResponse{Status: lockercache.StatusHeld}
 		err = d.DB.QueryRow(c.Context(),
-			`SELECT l.locker_id, l.owner_student_id, ll.name
+			`SELECT l.locker_id, l.owner_student_id, l.location_id, ll.name
 			 FROM locker_info l
 			 JOIN locker_locations ll ON ll.location_id = l.location_id
 			 WHERE l.locker_id = $1`,
-			id).Scan(&lockerInfo.LockerID, &lockerInfo.Owner, &lockerInfo.LocationID)
+			id).Scan(&lockerInfo.LockerID, &lockerInfo.Owner, &lockerInfo.LocationID, &lockerInfo.LocationName)
 		if err != nil {
 			// 정보 조회 실패해도 hold는 성공했으므로 기본 정보만 반환
 			return c.Status(fiber.StatusCreated).JSON(HoldFallbackResponse{
 				Message:   "locker held successfully",
 				LockerID:  id,
-				ExpiresIn: "1 minutes",
+				ExpiresAt: expiresAt,
 			})
 		}
 
 		// 성공 → 201 + 사물함 정보
 		return c.Status(fiber.StatusCreated).JSON(HoldSuccessResponse{
 			Message:   "locker held successfully",
-			Locker:    lockerInfo,
-			ExpiresIn: "1 minutes",
+			Locker:    viewerFrom(c).apply(lockerInfo),
+			ExpiresAt: expiresAt,
+		})
+	}
+}
+
+// ExtendHold: hold 연장
+// - 내 hold가 유효(만료 전)해야 하고, 연장 횟수가 회차의 max_hold_extensions 미만이어야 함
+// - 만료 시각을 지금부터 회차 hold TTL 만큼으로 다시 설정 (DB + Redis)
+// ExtendHold godoc
+// @Summary      사물함 선점 연장
+// @Description  선점(hold) 중인 사물함의 만료 시각을 지금부터 hold TTL 만큼 연장합니다. 연장 가능 횟수는 회차 설정(기본 1회)으로 제한됩니다.
+// @Tags         lockers
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        Idempotency-Key header string false "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생"
+// @Param        id path int true "사물함 ID (선점한 사물함)" minimum(1) maximum(999) example(101)
+// @Success      200 {object} HoldExtendResponse "연장 완료 - 새 만료 시각 포함"
+// @Failure      400 {object} ErrorResponse "잘못된 요청 - 유효하지 않은 사물함 ID"
+// @Failure      401 {object} ErrorResponse "인증 필요 - JWT 토큰이 없거나 유효하지 않음"
+// @Failure      403 {object} ApplicationWindowResponse "신청 기간 외 (code=not_open_yet | closed)"
+// @Failure      409 {object} ErrorResponse "선점이 만료되었거나 없음 / 연장 횟수 초과"
+// @Failure      500 {object} ErrorResponse "서버 오류"
+// @Failure      503 {object} ErrorResponse "서비스 일시 불가 - Redis 서버 장애"
+// @Failure      422 {object} ErrorResponse "같은 Idempotency-Key를 다른 요청에 재사용"
+// @Router       /lockers/{id}/hold/extend [post]
+func ExtendHold(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+
+		// 신청 기간이 끝나면 연장도 막는다 (HoldLocker/ClaimLocker와 같은 기준)
+		rd, _, sc, err := callerSchedule(c, d)
+		if err != nil {
+			return err
+		}
+		if werr := checkApplicationWindow(sc, time.Now()); werr != nil {
+			return c.Status(fiber.StatusForbidden).JSON(werr)
+		}
+
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		// 1) 유효한 내 hold + 연장 횟수 제한 확인 후 만료 시각 갱신 (Redis 실패 시 되돌리려고 이전 만료 시각도 반환)
+		var (
+			expiresAt, prevExpiresAt time.Time
+			extendCount              int
+		)
+		err = tx.QueryRow(c.Context(),
+			`UPDATE locker_assignments a
+			    SET hold_expires_at = now() + $3 * interval '1 second',
+			        extend_count = a.extend_count + 1
+			   FROM locker_assignments prev
+			  WHERE a.locker_id = $1 AND a.user_serial_id = $2
+			    AND a.state = 'hold' AND a.hold_expires_at > now()
+			    AND a.extend_count < $4
+			    AND prev.assignment_id = a.assignment_id
+			RETURNING a.hold_expires_at::timestamptz, a.extend_count, prev.hold_expires_at::timestamptz`,
+			id, serialID, rd.HoldTTLSeconds(), rd.MaxExtensions,
+		).Scan(&expiresAt, &extendCount, &prevExpiresAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// 유효한 hold는 있는데 횟수만 다 쓴 경우를 구분해서 알려준다
+			var used int
+			if tx.QueryRow(c.Context(),
+				`SELECT extend_count FROM locker_assignments
+				  WHERE locker_id = $1 AND user_serial_id = $2
+				    AND state = 'hold' AND hold_expires_at > now()`,
+				id, serialID,
+			).Scan(&used) == nil {
+				return fiber.NewError(fiber.StatusConflict, "hold extension limit reached")
+			}
+			return fiber.NewError(fiber.StatusConflict, "hold expired or not found")
+		}
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.LockerHoldExtend, audit.TargetLocker, strconv.Itoa(id)).
+			Change(nil, map[string]any{"state": "hold", "user_serial_id": serialID, "hold_expires_at": expiresAt, "extend_count": extendCount})); err != nil {
+			log.Printf("ExtendHold: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		// 2) 커밋된 뒤에 Redis hold TTL도 같은 값으로 연장 (소유자 확인)
+		//    - 먼저 늘리면 커밋 실패 시 Redis만 오래 잡혀 있어 만료 처리 후에도 사물함이 막힌다.
+		//    - Redis 연장이 실패하면 DB 연장을 되돌린다 (보상 UPDATE).
+		if err := d.Holds.Extend(c.Context(), id, serialID, rd.HoldTTL); err != nil {
+			if rerr := revertHoldExtension(c, d, id, serialID, extendCount, expiresAt, prevExpiresAt); rerr != nil {
+				log.Printf("ExtendHold: failed to revert extension of locker %d for user %d: %v", id, serialID, rerr)
+			}
+			if errors.Is(err, holdstore.ErrNotOwner) {
+				// Redis 키가 이미 만료됨
+				return fiber.NewError(fiber.StatusConflict, "hold expired or not found")
+			}
+			return fiber.NewError(fiber.StatusServiceUnavailable, "locker holds are temporarily unavailable (redis error), please retry shortly")
+		}
+
+		return c.JSON(HoldExtendResponse{
+			Message:        "hold extended successfully",
+			LockerID:       id,
+			ExpiresAt:      expiresAt,
+			ExtensionsLeft: max(rd.MaxExtensions-extendCount, 0),
 		})
 	}
 }
 
+// revertHoldExtension: Redis 연장에 실패한 hold 연장을 DB에서 되돌린다 (만료 시각/횟수 복원 + 감사 로그)
+// - 그 사이 상태가 바뀌었으면(extend_count가 다르면) 아무것도 하지 않는다.
+func revertHoldExtension(c *fiber.Ctx, d Deps, id int, serialID int64, extendCount int, extendedTo, prevExpiresAt time.Time) error {
+	tx, err := d.DB.Begin(c.Context())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(c.Context())
+
+	ct, err := tx.Exec(c.Context(),
+		`UPDATE locker_assignments
+		    SET hold_expires_at = $4::timestamptz::timestamp, extend_count = extend_count - 1
+		  WHERE locker_id = $1 AND user_serial_id = $2 AND state = 'hold' AND extend_count = $3`,
+		id, serialID, extendCount, prevExpiresAt)
+	if err != nil || ct.RowsAffected() == 0 {
+		return err
+	}
+	if err := audit.Record(c.Context(), tx, requestAudit(c).
+		On(audit.LockerHoldExtendRevert, audit.TargetLocker, strconv.Itoa(id)).
+		Change(map[string]any{"hold_expires_at": extendedTo, "extend_count": extendCount},
+			map[string]any{"hold_expires_at": prevExpiresAt, "extend_count": extendCount - 1})); err != nil {
+		return err
+	}
+	return tx.Commit(c.Context())
+}
+
 // ConfirmLocker: "확정"
 // - 내 hold가 유효(만료 전)해야 함
 // - 트랜잭션으로 assignments를 confirmed로 바꾸고, locker_info.owner를 내 학번으로 설정
@@ -232,12 +459,14 @@ func HoldLocker(d Deps) fiber.Handler {
 // @Accept       json
 // @Produce      json
 // @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        Idempotency-Key header string false "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생"
 // @Param        id path int true "사물함 ID (선점한 사물함)" minimum(1) maximum(999) example(101)
 // @Success      200 {object} SimpleSuccessResponse "확정 완료"
 // @Failure      400 {object} ErrorResponse "잘못된 요청 - 유효하지 않은 사물함 ID"
 // @Failure      401 {object} ErrorResponse "인증 필요 - JWT 토큰이 없거나 유효하지 않음"
-// @Failure      409 {object} ErrorResponse "선점이 만료되었거나 없음 - hold 상태가 아니거나 5분이 경과함"
+// @Failure      409 {object} ErrorResponse "선점이 만료되었거나 없음 - hold 상태가 아니거나 hold TTL(회차 설정, 기본 1분)이 경과함"
 // @Failure      500 {object} ErrorResponse "서버 오류 - 데이터베이스 트랜잭션 실패"
+// @Failure      422 {object} ErrorResponse "같은 Idempotency-Key를 다른 요청에 재사용"
 // @Router       /lockers/{id}/confirm [post]
 func ConfirmLocker(d Deps) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -279,11 +508,24 @@ func ConfirmLocker(d Deps) fiber.Handler {
 			return fiber.ErrConflict
 		}
 
+		// 3) 감사 로그
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.LockerConfirm, audit.TargetLocker, strconv.Itoa(id)).
+			Change(map[string]any{"state": "hold", "user_serial_id": serialID},
+				map[string]any{"state": "confirmed", "owner_serial_id": serialID})); err != nil {
+			log.Printf("ConfirmLocker: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
 		// 커밋
 		if err := tx.Commit(c.Context()); err != nil {
 			return fiber.ErrInternalServerError
 		}
 
+		// 확정됐으므로 내 hold 키 정리 (베스트 에포트, 남아 있어도 TTL로 사라짐)
+		_ = d.Holds.Release(c.Context(), id, serialID)
+		d.Lockers.Publish(c.Context(), id, lockercache.EventConfirm)
+
 		// 성공 → 200
 		return c.JSON(SimpleSuccessResponse{
 			Message: "locker confirmed successfully",
@@ -291,52 +533,191 @@ func ConfirmLocker(d Deps) fiber.Handler {
 	}
 }
 
-/*
-// DirectConfirmLocker: 바로 확정 (Hold 단계 생략)
-func DirectConfirmLocker(d Deps) fiber.Handler {
-    return func(c *fiber.Ctx) error {
-        id, err := strconv.Atoi(c.Params("id"))
-        if err != nil {
-            return fiber.ErrBadRequest
-        }
-
-        student, _ := c.Locals("student_id").(string)
-        if student == "" {
-            return fiber.ErrUnauthorized
-        }
-
-        // 트랜잭션으로 한 번에 처리
-        tx, err := d.DB.Begin(c.Context())
-        if err != nil {
-            return fiber.ErrInternalServerError
-        }
-        defer tx.Rollback(c.Context())
-
-        // 1) 사물함이 비어있는지 확인하고 바로 소유자 설정
-        ct, err := tx.Exec(c.Context(),
-            `UPDATE locker_info SET owner=$1 WHERE locker_id=$2 AND owner IS NULL`,
-            student, id)
-        if err != nil || ct.RowsAffected() == 0 {
-            return fiber.NewError(fiber.StatusConflict, "locker already taken")
-        }
-
-        // 2) 히스토리 기록 (confirmed 상태로 바로)
-        _, err = tx.Exec(c.Context(),
-            `INSERT INTO locker_assignments(locker_id, student_id, state, confirmed_at)
-             VALUES ($1,$2,'confirmed', now())`,
-            id, student)
-        if err != nil {
-            return fiber.ErrInternalServerError
-        }
-
-        if err := tx.Commit(c.Context()); err != nil {
-            return fiber.ErrInternalServerError
-        }
-
-        return c.JSON(fiber.Map{"message": "locker confirmed successfully"})
-    }
+// ClaimLocker: 바로 확정 (Hold 단계 생략, 회차 direct_confirm=true 일 때만)
+// 1) holdstore.Acquire로 잠깐 hold를 잡아 동시에 들어온 hold/claim과 경쟁 (사용자당 hold 1개 규칙도 동일 적용)
+// 2) 트랜잭션으로 locker_info.owner 설정 + confirmed 배정 기록
+// 3) 성공/실패와 무관하게 잡았던 hold는 해제
+// - 사용자당 활성 배정 1건은 부분 유니크 인덱스가 보장 → 위반 시 409
+// ClaimLocker godoc
+// @Summary      사물함 바로 확정
+// @Description  hold 단계 없이 한 번의 요청으로 사물함을 확정합니다. 현재 회차에 바로 확정(direct confirm)이 켜져 있을 때만 사용할 수 있으며, 신청 기간 외에는 접근이 불가능합니다.
+// @Tags         lockers
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        Idempotency-Key header string false "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생"
+// @Param        id path int true "사물함 ID (1-999 범위)" minimum(1) maximum(999) example(101)
+// @Success      200 {object} ClaimSuccessResponse "확정 완료"
+// @Failure      400 {object} ErrorResponse "잘못된 요청 - 유효하지 않은 사물함 ID"
+// @Failure      401 {object} ErrorResponse "인증 필요 - JWT 토큰이 없거나 유효하지 않음"
+// @Failure      403 {object} ApplicationWindowResponse "신청 기간 외 (code=not_open_yet | closed). 현재 회차에서 바로 확정이 꺼져 있거나 회차 신청 자격이 없으면 ErrorResponse"
+// @Failure      404 {object} ErrorResponse "locker not found"
+// @Failure      409 {object} ErrorResponse "이미 다른 사용자가 선점/소유 중이거나 본인이 이미 활성 사물함을 보유 중, 또는 사용 중지된 사물함"
+// @Failure      422 {object} ErrorResponse "같은 Idempotency-Key를 다른 요청에 재사용"
+// @Failure      500 {object} ErrorResponse "서버 오류 - 데이터베이스 트랜잭션 실패"
+// @Failure      503 {object} ErrorResponse "서비스 일시 불가 - Redis 서버 장애"
+// @Router       /lockers/{id}/claim [post]
+func ClaimLocker(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rd, err := round.Current(c.Context(), d.DB)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		if !rd.DirectConfirm {
+			return fiber.NewError(fiber.StatusForbidden, "direct confirm is not enabled for this round")
+		}
+
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+		studentID, _ := c.Locals("student_id").(string)
+		st, err := checkEligibility(c, d, rd, studentID, "")
+		if err != nil {
+			return err
+		}
+		if werr := checkApplicationWindow(rd.ScheduleFor(st), time.Now()); werr != nil {
+			return c.Status(fiber.StatusForbidden).JSON(werr)
+		}
+
+		if err := checkInService(c, d, id); err != nil {
+			return err
+		}
+
+		// 1) 진행 중인 hold와 경쟁하기 위해 짧게 hold 획득
+		if err := d.Holds.Acquire(c.Context(), id, serialID, rd.HoldTTL); err != nil {
+			switch {
+			case errors.Is(err, holdstore.ErrLockerHeld):
+				return fiber.NewError(fiber.StatusConflict, "Locker already held by someone")
+			case errors.Is(err, holdstore.ErrUserHolding):
+				return fiber.NewError(fiber.StatusConflict, "You already hold another locker")
+			default:
+				return fiber.NewError(fiber.StatusServiceUnavailable, "locker holds are temporarily unavailable (redis error), please retry shortly")
+			}
+		}
+		// 3) 결과와 무관하게 hold 해제 (베스트 에포트)
+		defer func() { _ = d.Holds.Release(c.Context(), id, serialID) }()
+
+		// 2) 트랜잭션으로 confirmed 기록 + 소유자 설정
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		// 배정을 먼저 기록해서 부분 유니크 인덱스(사물함당/사용자당 활성 1건)가 충돌을 409로 잡게 한다
+		var confirmedAt time.Time
+		err = tx.QueryRow(c.Context(),
+			`INSERT INTO locker_assignments(locker_id, user_serial_id, state, confirmed_at)
+			 VALUES ($1,$2,'confirmed', now())
+			 RETURNING confirmed_at::timestamptz`,
+			id, serialID).Scan(&confirmedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case "23505":
+					return fiber.NewError(fiber.StatusConflict, "you already have an active locker or the locker is taken")
+				case "23503": // 없는 사물함 (FK 위반)
+					return fiber.NewError(fiber.StatusNotFound, "locker not found")
+				}
+			}
+			return fiber.ErrInternalServerError
+		}
+
+		ct, err := tx.Exec(c.Context(),
+			`UPDATE locker_info SET owner_serial_id=$1, owner_student_id=$2
+			  WHERE locker_id=$3 AND owner_serial_id IS NULL`,
+			serialID, studentID, id)
+		if err != nil {
+			// locker_info_owner_serial_id_key: 이미 다른 사물함의 소유자
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return fiber.NewError(fiber.StatusConflict, "you already have an active locker or the locker is taken")
+			}
+			return fiber.ErrInternalServerError
+		}
+		if ct.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusConflict, "locker already taken")
+		}
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.LockerClaim, audit.TargetLocker, strconv.Itoa(id)).
+			Change(map[string]any{"state": "free"},
+				map[string]any{"state": "confirmed", "owner_serial_id": serialID, "round_id": rd.ID})); err != nil {
+			log.Printf("ClaimLocker: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		d.Lockers.Publish(c.Context(), id, lockercache.EventConfirm)
+
+		return c.JSON(ClaimSuccessResponse{
+			Message:     "locker claimed successfully",
+			LockerID:    id,
+			ConfirmedAt: confirmedAt,
+		})
+	}
+}
+
+// checkApplicationWindow: 학생의 신청 기간 체크 (시작 전/마감 후 → 403 본문, 기간 안이면 nil)
+// - 우선 순위 그룹이면 그룹 이름과 그룹 시작 시각을 알려준다.
+func checkApplicationWindow(sc round.Schedule, now time.Time) *ApplicationWindowResponse {
+	resp := &ApplicationWindowResponse{OpensAt: sc.OpensAt, ClosesAt: sc.ClosesAt, ServerTime: now}
+	if sc.Group != nil {
+		resp.Group = sc.Group.Name
+	}
+	switch {
+	case sc.NotOpenYet(now):
+		resp.Code = WindowNotOpenYet
+		resp.Error = "아직 신청 기간이 아닙니다. 신청 시작: " + formatWindowTime(*sc.OpensAt)
+		if sc.Group != nil {
+			resp.Error = "아직 신청 기간이 아닙니다. " + sc.Group.Name + " 신청 시작: " + formatWindowTime(*sc.OpensAt)
+		}
+	case sc.Closed(now):
+		resp.Code = WindowClosed
+		resp.Error = "신청 기간이 마감되었습니다. 신청 마감: " + formatWindowTime(*sc.ClosesAt)
+	default:
+		return nil
+	}
+	return resp
+}
+
+// formatWindowTime: 안내 문구용 서버 시간대(Asia/Seoul) 시각
+func formatWindowTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// recordHold: hold 행 INSERT + 감사 로그를 한 트랜잭션으로 기록하고 만료 시각을 돌려준다
+func recordHold(c *fiber.Ctx, d Deps, rd *round.Round, id int, serialID int64) (time.Time, error) {
+	var expiresAt time.Time
+	tx, err := d.DB.Begin(c.Context())
+	if err != nil {
+		return expiresAt, err
+	}
+	defer tx.Rollback(c.Context())
+
+	err = tx.QueryRow(c.Context(),
+		`INSERT INTO locker_assignments(locker_id, user_serial_id, state, hold_expires_at)
+		 VALUES ($1,$2,'hold', now() + $3 * interval '1 second')
+		 RETURNING hold_expires_at::timestamptz`,
+		id, serialID, rd.HoldTTLSeconds()).Scan(&expiresAt)
+	if err != nil {
+		return expiresAt, err
+	}
+	if err := audit.Record(c.Context(), tx, requestAudit(c).
+		On(audit.LockerHold, audit.TargetLocker, strconv.Itoa(id)).
+		Change(map[string]any{"state": "free"},
+			map[string]any{"state": "hold", "user_serial_id": serialID, "hold_expires_at": expiresAt, "round_id": rd.ID})); err != nil {
+		return expiresAt, err
+	}
+	return expiresAt, tx.Commit(c.Context())
 }
-*/
 
 // ReleaseLocker: "해제"
 // - confirmed 상태인 내 사물함을 취소하고, locker_info.owner=NULL
@@ -347,12 +728,14 @@ func DirectConfirmLocker(d Deps) fiber.Handler {
 // @Accept       json
 // @Produce      json
 // @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        Idempotency-Key header string false "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생"
 // @Param        id path int true "사물함 ID (소유한 사물함)" minimum(1) maximum(999) example(101)
 // @Success      200 {object} SimpleSuccessResponse "해제 완료"
 // @Failure      400 {object} ErrorResponse "잘못된 요청 - 유효하지 않은 사물함 ID"
 // @Failure      401 {object} ErrorResponse "인증 필요 - JWT 토큰이 없거나 유효하지 않음"
 // @Failure      404 {object} ErrorResponse "사물함을 찾을 수 없음 - 소유하지 않은 사물함"
 // @Failure      500 {object} ErrorResponse "서버 오류 - 데이터베이스 트랜잭션 실패"
+// @Failure      422 {object} ErrorResponse "같은 Idempotency-Key를 다른 요청에 재사용"
 // @Router       /lockers/{id}/release [post]
 func ReleaseLocker(d Deps) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -396,21 +779,32 @@ func ReleaseLocker(d Deps) fiber.Handler {
 			return fiber.NewError(fiber.StatusNotFound, "No locker ownership found to release")
 		}
 
-		// 3) Remove any lingering hold assignments
+		// 3) 남아 있을지 모르는 hold 행도 취소 (히스토리 보존을 위해 지우지 않음)
 		_, err = tx.Exec(c.Context(),
-			`DELETE FROM locker_assignments WHERE locker_id=$1 AND user_serial_id=$2 AND state='hold'`,
+			`UPDATE locker_assignments SET state='cancelled', released_at=now()
+			  WHERE locker_id=$1 AND user_serial_id=$2 AND state='hold'`,
 			id, serialID)
 		if err != nil {
 			return fiber.ErrInternalServerError
 		}
 
+		// 4) 감사 로그
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.LockerRelease, audit.TargetLocker, strconv.Itoa(id)).
+			Change(map[string]any{"state": "confirmed", "owner_serial_id": serialID},
+				map[string]any{"state": "cancelled"})); err != nil {
+			log.Printf("ReleaseLocker: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
 		// 커밋
 		if err := tx.Commit(c.Context()); err != nil {
 			return fiber.ErrInternalServerError
 		}
 
-		// (옵션) 혹시 남아있을지 모르는 hold 키 제거(베스트 에포트)
-		_, _ = d.RDB.Del(c.Context(), "locker:hold:"+strconv.Itoa(id)).Result()
+		// (옵션) 혹시 남아있을지 모르는 내 hold 키 제거(베스트 에포트, 소유자 확인)
+		_ = d.Holds.Release(c.Context(), id, serialID)
+		d.Lockers.Publish(c.Context(), id, lockercache.EventRelease)
 
 		return c.JSON(SimpleSuccessResponse{
 			Message: "locker released successfully",
@@ -422,17 +816,19 @@ func ReleaseLocker(d Deps) fiber.Handler {
 // - hold 상태인 사물함 예약을 취소합니다.
 // ReleaseHold godoc
 // @Summary      사물함 hold 해제
-// @Description  hold 상태인 사물함 예약을 취소합니다. 사물함이 다시 사용 가능해집니다.
+// @Description  hold 상태인 사물함 예약을 취소합니다. 사물함이 다시 사용 가능해집니다. 예약 기록은 지우지 않고 cancelled 상태로 남습니다.
 // @Tags         lockers
 // @Accept       json
 // @Produce      json
 // @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        Idempotency-Key header string false "재시도 안전 키 - 같은 키로 재요청하면 저장된 응답을 재생"
 // @Param        id path int true "사물함 ID (hold 상태의 사물함)" minimum(1) maximum(999) example(101)
 // @Success      200 {object} SimpleSuccessResponse "hold 해제 완료"
 // @Failure      400 {object} ErrorResponse "잘못된 요청 - 유효하지 않은 사물함 ID"
 // @Failure      401 {object} ErrorResponse "인증 필요 - JWT 토큰이 없거나 유효하지 않음"
 // @Failure      404 {object} ErrorResponse "사물함을 찾을 수 없음 - hold 상태가 아님"
 // @Failure      500 {object} ErrorResponse "서버 오류 - 데이터베이스 트랜잭션 실패"
+// @Failure      422 {object} ErrorResponse "같은 Idempotency-Key를 다른 요청에 재사용"
 // @Router       /lockers/{id}/release-hold [post]
 func ReleaseHold(d Deps) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -452,15 +848,25 @@ func ReleaseHold(d Deps) fiber.Handler {
 		}
 		defer tx.Rollback(c.Context())
 
-		// hold 상태 해제
-		ct, err := tx.Exec(c.Context(),
-			`DELETE FROM locker_assignments WHERE locker_id=$1 AND user_serial_id=$2 AND state='hold'`,
-			id, serialID)
+		// hold 상태 해제: 행은 지우지 않고 cancelled로 남긴다 (누가 언제 잡고 놓았는지 히스토리 보존)
+		var expiresAt *time.Time
+		err = tx.QueryRow(c.Context(),
+			`UPDATE locker_assignments SET state='cancelled', released_at=now()
+			  WHERE locker_id=$1 AND user_serial_id=$2 AND state='hold'
+			RETURNING hold_expires_at::timestamptz`,
+			id, serialID).Scan(&expiresAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "No hold found to release")
+		}
 		if err != nil {
 			return fiber.ErrInternalServerError
 		}
-		if ct.RowsAffected() == 0 {
-			return fiber.NewError(fiber.StatusNotFound, "No hold found to release")
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.LockerHoldRelease, audit.TargetLocker, strconv.Itoa(id)).
+			Change(map[string]any{"state": "hold", "user_serial_id": serialID, "hold_expires_at": expiresAt},
+				map[string]any{"state": "cancelled"})); err != nil {
+			log.Printf("ReleaseHold: %v", err)
+			return fiber.ErrInternalServerError
 		}
 
 		// 커밋
@@ -468,8 +874,9 @@ func ReleaseHold(d Deps) fiber.Handler {
 			return fiber.ErrInternalServerError
 		}
 
-		// Redis 키 제거 (베스트 에포트)
-		_, _ = d.RDB.Del(c.Context(), "locker:hold:"+strconv.Itoa(id)).Result()
+		// 내 Redis hold 키 제거 (베스트 에포트, 소유자 확인 → 이미 만료 후 남이 새로 잡은 hold는 건드리지 않음)
+		_ = d.Holds.Release(c.Context(), id, serialID)
+		d.Lockers.Publish(c.Context(), id, lockercache.EventRelease)
 
 		return c.JSON(SimpleSuccessResponse{
 			Message: "hold released successfully",
@@ -496,14 +903,14 @@ func GetMyLocker(d Deps) fiber.Handler {
 			return fiber.ErrUnauthorized
 		}
 
-		var it LockerResponse
+		it := LockerResponse{Status: lockercache.StatusTaken}
 		err := d.DB.QueryRow(c.Context(),
-			`SELECT l.locker_id, l.owner_student_id, ll.name
+			`SELECT l.locker_id, l.owner_student_id, l.owner_serial_id, l.location_id, ll.name
                FROM locker_info l
                JOIN locker_locations ll ON ll.location_id = l.location_id
               WHERE l.owner_serial_id = $1`,
 			serialID,
-		).Scan(&it.LockerID, &it.Owner, &it.LocationID)
+		).Scan(&it.LockerID, &it.Owner, &it.OwnerSerialID, &it.LocationID, &it.LocationName)
 
 		if err != nil {
 			if err == pgx.ErrNoRows {
@@ -512,13 +919,17 @@ func GetMyLocker(d Deps) fiber.Handler {
 			return fiber.ErrInternalServerError
 		}
 
+		it = viewerFrom(c).apply(it)
 		return c.JSON(MyLockerResponse{Locker: &it})
 	}
 }
 
 /*
 [중요 포인트 요약]
-- Hold: Redis SETNX + TTL → 첫 클릭만 true. 이후 DB에 'hold' 기록.
+- Hold: holdstore(Lua) 사물함/사용자 키 동시 설정 + TTL → 첫 클릭만 성공. 이후 DB에 'hold' 기록.
+- 해제: holdstore.Release가 저장된 serial_id를 비교한 뒤에만 키를 지운다.
+- Extend: 회차 max_hold_extensions 까지 hold 만료 시각을 TTL 만큼 다시 설정 (DB + Redis).
+- Claim: 회차 direct_confirm=true면 hold 없이 소유자 설정 + confirmed 기록을 한 트랜잭션으로.
 - Confirm: DB 트랜잭션으로 유효 hold 확인 → confirmed 전환 + owner 설정.
 - Release: confirmed를 cancelled로 → owner 해제.
 - “최후의 안전망”: PostgreSQL 부분 유니크 인덱스(사물함당/사용자당 활성 1건).
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+// 사물함 속성 (locker_attributes, 012 마이그레이션)
+// - 크기/층/콘센트 근처 여부는 목록 필터·정렬에 쓰이고, out_of_service면 hold/claim이 거절된다.
+// - 관리자 메모(service_note)는 관리자 응답에만 포함한다.
+
+// LockerAttributes 사물함 속성 (입력되지 않은 크기/층은 null)
+type LockerAttributes struct {
+	Size         *string `json:"size" enums:"small,medium,large" example:"medium"`
+	Row          *string `json:"row" enums:"top,middle,bottom" example:"middle"`
+	NearOutlet   bool    `json:"near_outlet" example:"false"`
+	OutOfService bool    `json:"out_of_service" example:"false"`
+}
+
+// attributesOf: 스냅샷 사물함의 속성
+func attributesOf(l lockercache.Locker) *LockerAttributes {
+	return &LockerAttributes{
+		Size:         l.Size,
+		Row:          l.RowPosition,
+		NearOutlet:   l.NearOutlet,
+		OutOfService: l.OutOfService,
+	}
+}
+
+// UpdateLockerAttributesRequest 사물함 속성 수정 요청
+// - 바꿀 값만 보낸다. size/row/service_note는 빈 문자열("")을 보내면 지운다.
+type UpdateLockerAttributesRequest struct {
+	Size         *string `json:"size,omitempty" example:"large"`
+	Row          *string `json:"row,omitempty" example:"bottom"`
+	NearOutlet   *bool   `json:"near_outlet,omitempty" example:"true"`
+	OutOfService *bool   `json:"out_of_service,omitempty" example:"true"`
+	ServiceNote  *string `json:"service_note,omitempty" example:"문 잠금장치 고장"`
+}
+
+// AdminLockerAttributesResponse 사물함 속성 (관리자용, 메모/수정 이력 포함)
+type AdminLockerAttributesResponse struct {
+	LockerID int `json:"locker_id" example:"101"`
+	LockerAttributes
+	ServiceNote *string   `json:"service_note" example:"문 잠금장치 고장"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	UpdatedBy   *int64    `json:"updated_by" example:"123456789012"`
+}
+
+// UpdateLockerAttributes godoc
+// @Summary      사물함 속성 수정 (관리자)
+// @Description  사물함의 크기(small/medium/large), 층(top/middle/bottom), 콘센트 근처 여부, 사용 중지 여부와 메모를 수정합니다. 보낸 필드만 바뀌고, size/row/service_note에 빈 문자열을 보내면 지워집니다. 사용 중지로 바꿔도 현재 소유자/hold는 그대로 유지되며, 이후 hold/claim만 거절됩니다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        id path int true "사물함 ID" example(101)
+// @Param        payload body UpdateLockerAttributesRequest true "바꿀 속성"
+// @Success      200 {object} AdminLockerAttributesResponse
+// @Failure      400 {object} ErrorResponse "invalid size / row / nothing to update"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      404 {object} ErrorResponse "locker not found"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/lockers/{id}/attributes [patch]
+func UpdateLockerAttributes(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		adminSerial, _ := c.Locals("user_serial_id").(int64)
+
+		var req UpdateLockerAttributesRequest
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.ErrBadRequest
+		}
+		if req.Size == nil && req.Row == nil && req.NearOutlet == nil && req.OutOfService == nil && req.ServiceNote == nil {
+			return fiber.NewError(fiber.StatusBadRequest, "nothing to update")
+		}
+
+		// 문자열 필드: nil = 유지, "" = NULL로 지움
+		size, err := optionalEnum(req.Size, "size", lockercache.Sizes...)
+		if err != nil {
+			return err
+		}
+		row, err := optionalEnum(req.Row, "row", lockercache.RowPositions...)
+		if err != nil {
+			return err
+		}
+		var note *string
+		if req.ServiceNote != nil {
+			v := strings.TrimSpace(*req.ServiceNote)
+			if v != "" {
+				note = &v
+			}
+		}
+
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		// 사물함 존재 확인 후 속성 행이 없으면 기본값으로 만든다
+		_, err = tx.Exec(c.Context(),
+			`INSERT INTO locker_attributes (locker_id)
+			 SELECT locker_id FROM locker_info WHERE locker_id = $1
+			 ON CONFLICT (locker_id) DO NOTHING`, id)
+		if err != nil {
+			log.Printf("UpdateLockerAttributes: failed to prepare attributes for locker %d: %v", id, err)
+			return fiber.ErrInternalServerError
+		}
+
+		// 감사 로그용 변경 전 값 (행 잠금)
+		before := AdminLockerAttributesResponse{LockerID: id}
+		err = tx.QueryRow(c.Context(),
+			`SELECT size, row_position, near_outlet, out_of_service, service_note, updated_at::timestamptz, updated_by
+			   FROM locker_attributes WHERE locker_id = $1 FOR UPDATE`, id,
+		).Scan(&before.Size, &before.Row, &before.NearOutlet, &before.OutOfService, &before.ServiceNote, &before.UpdatedAt, &before.UpdatedBy)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "locker not found")
+		}
+		if err != nil {
+			log.Printf("UpdateLockerAttributes: failed to load attributes for locker %d: %v", id, err)
+			return fiber.ErrInternalServerError
+		}
+
+		out := AdminLockerAttributesResponse{LockerID: id}
+		err = tx.QueryRow(c.Context(),
+			`UPDATE locker_attributes
+			    SET size           = CASE WHEN $2 THEN $3 ELSE size END,
+			        row_position   = CASE WHEN $4 THEN $5 ELSE row_position END,
+			        near_outlet    = COALESCE($6, near_outlet),
+			        out_of_service = COALESCE($7, out_of_service),
+			        service_note   = CASE WHEN $8 THEN $9 ELSE service_note END,
+			        updated_at     = now(),
+			        updated_by     = $10
+			  WHERE locker_id = $1
+			RETURNING size, row_position, near_outlet, out_of_service, service_note, updated_at::timestamptz, updated_by`,
+			id, req.Size != nil, size, req.Row != nil, row,
+			req.NearOutlet, req.OutOfService, req.ServiceNote != nil, note, adminSerial,
+		).Scan(&out.Size, &out.Row, &out.NearOutlet, &out.OutOfService, &out.ServiceNote, &out.UpdatedAt, &out.UpdatedBy)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "locker not found")
+		}
+		if err != nil {
+			log.Printf("UpdateLockerAttributes: failed to update attributes for locker %d: %v", id, err)
+			return fiber.ErrInternalServerError
+		}
+		if err := audit.Record(c.Context(), tx, requestAudit(c).
+			On(audit.AdminLockerAttributes, audit.TargetLocker, strconv.Itoa(id)).Change(before, out)); err != nil {
+			log.Printf("UpdateLockerAttributes: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		d.Lockers.Publish(c.Context(), id, lockercache.EventAdmin)
+		log.Printf("UpdateLockerAttributes: locker %d attributes updated by admin %d", id, adminSerial)
+		return c.JSON(out)
+	}
+}
+
+// optionalEnum: nil/""이면 nil, 아니면 allowed 중 하나인지 검증
+func optionalEnum(v *string, name string, allowed ...string) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	s := strings.TrimSpace(*v)
+	if s == "" {
+		return nil, nil
+	}
+	for _, a := range allowed {
+		if s == a {
+			return &s, nil
+		}
+	}
+	return nil, fiber.NewError(fiber.StatusBadRequest, "invalid "+name+": must be one of "+strings.Join(allowed, ", "))
+}
+
+// checkInService: 사용 중지된 사물함이면 409 (속성 행이 없으면 사용 가능)
+func checkInService(c *fiber.Ctx, d Deps, lockerID int) error {
+	var outOfService bool
+	err := d.DB.QueryRow(c.Context(),
+		`SELECT out_of_service FROM locker_attributes WHERE locker_id = $1`, lockerID,
+	).Scan(&outOfService)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fiber.ErrInternalServerError
+	}
+	if outOfService {
+		return fiber.NewError(fiber.StatusConflict, "locker is out of service")
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GET /lockers 필터/정렬/페이지네이션 (lockercache 스냅샷을 메모리에서 거른다)
+
+// 목록 페이지 크기
+// - limit, cursor 둘 다 없으면 기존처럼 전체를 한 번에 반환 (페이지네이션은 요청한 클라이언트만)
+const (
+	defaultLockerPageSize = 100
+	maxLockerPageSize     = 500
+)
+
+// lockerSorts sort 파라미터 → 정렬 키 (작을수록 앞, 같으면 locker_id 순)
+// - 크기/층은 lockercache.Sizes / RowPositions 순서, 값이 없으면 항상 뒤로
+var lockerSorts = map[string]func(l lockercache.Locker) int{
+	"size":        func(l lockercache.Locker) int { return rank(lockercache.Sizes, l.Size, false) },
+	"-size":       func(l lockercache.Locker) int { return rank(lockercache.Sizes, l.Size, true) },
+	"row":         func(l lockercache.Locker) int { return rank(lockercache.RowPositions, l.RowPosition, false) },
+	"-row":        func(l lockercache.Locker) int { return rank(lockercache.RowPositions, l.RowPosition, true) },
+	"near_outlet": func(l lockercache.Locker) int { return boolRank(!l.NearOutlet) },
+}
+
+// lockerFilter ListLockers 쿼리 파라미터
+type lockerFilter struct {
+	locationID int                            // 0이면 전체
+	statuses   map[string]bool                // 비어 있으면 전체
+	minID      int                            // 0이면 하한 없음
+	maxID      int                            // 0이면 상한 없음
+	sizes      map[string]bool                // 비어 있으면 전체
+	rows       map[string]bool                // 비어 있으면 전체
+	nearOutlet *bool                          // nil이면 전체
+	sortKey    func(l lockercache.Locker) int // nil이면 locker_id 순
+
+	// cursor: 정렬 키가 (afterKey, afterID)인 사물함 다음부터
+	hasCursor bool
+	afterKey  int
+	afterID   int
+	limit     int // 0이면 페이지네이션 없이 전체
+}
+
+func parseLockerFilter(c *fiber.Ctx) (lockerFilter, error) {
+	var f lockerFilter
+
+	ints := []struct {
+		name string
+		dst  *int
+	}{
+		{"location_id", &f.locationID},
+		{"min_id", &f.minID},
+		{"max_id", &f.maxID},
+		{"limit", &f.limit},
+	}
+	for _, p := range ints {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			return f, fiber.NewError(fiber.StatusBadRequest, "invalid "+p.name)
+		}
+		*p.dst = v
+	}
+	if f.limit > maxLockerPageSize {
+		f.limit = maxLockerPageSize
+	}
+	if f.minID > 0 && f.maxID > 0 && f.minID > f.maxID {
+		return f, fiber.NewError(fiber.StatusBadRequest, "min_id must not be greater than max_id")
+	}
+
+	var err error
+	if f.statuses, err = parseEnumList(c.Query("status"), "status",
+		lockercache.StatusFree, lockercache.StatusHeld, lockercache.StatusTaken, lockercache.StatusOutOfService); err != nil {
+		return f, err
+	}
+	if f.sizes, err = parseEnumList(c.Query("size"), "size", lockercache.Sizes...); err != nil {
+		return f, err
+	}
+	if f.rows, err = parseEnumList(c.Query("row"), "row", lockercache.RowPositions...); err != nil {
+		return f, err
+	}
+	if raw := c.Query("near_outlet"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return f, fiber.NewError(fiber.StatusBadRequest, "invalid near_outlet")
+		}
+		f.nearOutlet = &v
+	}
+
+	if raw := c.Query("sort"); raw != "" && raw != "locker_id" {
+		key, ok := lockerSorts[raw]
+		if !ok {
+			return f, fiber.NewError(fiber.StatusBadRequest, "invalid sort: must be locker_id, size, -size, row, -row or near_outlet")
+		}
+		f.sortKey = key
+	}
+
+	// cursor: locker_id 순이면 "{locker_id}", 속성 정렬이면 "{정렬 키}-{locker_id}"
+	if raw := c.Query("cursor"); raw != "" {
+		keyPart, idPart, sorted := strings.Cut(raw, "-")
+		if !sorted {
+			idPart = keyPart
+		}
+		id, err := strconv.Atoi(idPart)
+		if err != nil || sorted != (f.sortKey != nil) {
+			return f, fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+		}
+		if sorted {
+			if f.afterKey, err = strconv.Atoi(keyPart); err != nil {
+				return f, fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+			}
+		}
+		f.hasCursor, f.afterID = true, id
+		if f.limit == 0 {
+			f.limit = defaultLockerPageSize
+		}
+	}
+	return f, nil
+}
+
+// parseEnumList: 쉼표로 구분된 값 목록 검증 (빈 문자열이면 nil = 전체)
+func parseEnumList(raw, name string, allowed ...string) (map[string]bool, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	out := map[string]bool{}
+	for _, v := range strings.Split(raw, ",") {
+		v = strings.TrimSpace(v)
+		ok := false
+		for _, a := range allowed {
+			ok = ok || v == a
+		}
+		if !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "invalid "+name+": must be one of "+strings.Join(allowed, ", "))
+		}
+		out[v] = true
+	}
+	return out, nil
+}
+
+func (f lockerFilter) match(l lockercache.Locker, status string) bool {
+	switch {
+	case f.locationID > 0 && l.LocationID != f.locationID:
+		return false
+	case f.minID > 0 && l.LockerID < f.minID:
+		return false
+	case f.maxID > 0 && l.LockerID > f.maxID:
+		return false
+	case len(f.statuses) > 0 && !f.statuses[status]:
+		return false
+	case len(f.sizes) > 0 && (l.Size == nil || !f.sizes[*l.Size]):
+		return false
+	case len(f.rows) > 0 && (l.RowPosition == nil || !f.rows[*l.RowPosition]):
+		return false
+	case f.nearOutlet != nil && l.NearOutlet != *f.nearOutlet:
+		return false
+	}
+	return true
+}
+
+// sortLockers: 정렬 키 순으로 정렬 (스냅샷이 locker_id 순이므로 안정 정렬이면 같은 키끼리는 locker_id 순)
+func (f lockerFilter) sortLockers(lockers []lockercache.Locker) {
+	if f.sortKey == nil {
+		return
+	}
+	sort.SliceStable(lockers, func(i, j int) bool {
+		return f.sortKey(lockers[i]) < f.sortKey(lockers[j])
+	})
+}
+
+// afterCursor: 정렬 순서상 cursor 다음에 오는 사물함인지
+func (f lockerFilter) afterCursor(l lockercache.Locker) bool {
+	if !f.hasCursor {
+		return true
+	}
+	if f.sortKey == nil {
+		return l.LockerID > f.afterID
+	}
+	key := f.sortKey(l)
+	return key > f.afterKey || (key == f.afterKey && l.LockerID > f.afterID)
+}
+
+// cursorOf: 이 사물함 다음부터 이어서 보기 위한 cursor
+func (f lockerFilter) cursorOf(l lockercache.Locker) string {
+	if f.sortKey == nil {
+		return strconv.Itoa(l.LockerID)
+	}
+	return strconv.Itoa(f.sortKey(l)) + "-" + strconv.Itoa(l.LockerID)
+}
+
+// rank: order 안에서의 위치 (desc면 역순). 값이 없으면 항상 맨 뒤
+func rank(order []string, v *string, desc bool) int {
+	if v == nil {
+		return len(order)
+	}
+	for i, o := range order {
+		if o == *v {
+			if desc {
+				return len(order) - 1 - i
+			}
+			return i
+		}
+	}
+	return len(order)
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 사물함 배정 히스토리 (locker_assignments 타임라인)
+// - 배정 한 건 = hold(또는 바로 claim)부터 확정/해제/만료까지. 행은 지우지 않으므로 전체 이력이 남는다.
+// - 최신순, assignment_id 기준 cursor 페이지네이션 (018_assignment_history_index.sql)
+
+const (
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 200
+)
+
+// 타임라인 이벤트 종류
+const (
+	HistoryHold        = "hold"         // hold로 선점
+	HistoryClaim       = "claim"        // hold 없이 바로 확정 (direct_confirm 회차)
+	HistoryConfirm     = "confirm"      // hold → 확정
+	HistoryExpire      = "expire"       // hold 만료 (시각은 hold_expires_at)
+	HistoryHoldRelease = "hold_release" // hold 직접 해제
+	HistoryRelease     = "release"      // 확정된 사물함 해제
+)
+
+// AssignmentEvent 배정 한 건 안의 이벤트
+type AssignmentEvent struct {
+	Type string    `json:"type" enums:"hold,claim,confirm,expire,hold_release,release" example:"hold"`
+	At   time.Time `json:"at"`
+}
+
+// AssignmentHistoryItem 배정 한 건 (이벤트는 시간 순)
+type AssignmentHistoryItem struct {
+	AssignmentID  int64             `json:"assignment_id" example:"1"`
+	LockerID      int               `json:"locker_id" example:"101"`
+	LocationName  string            `json:"location_name" example:"정보관 B1 엘리베이터"`
+	State         string            `json:"state" enums:"hold,confirmed,cancelled,expired" example:"confirmed"`
+	CreatedAt     time.Time         `json:"created_at"`
+	HoldExpiresAt *time.Time        `json:"hold_expires_at,omitempty"`
+	ConfirmedAt   *time.Time        `json:"confirmed_at,omitempty"`
+	ReleasedAt    *time.Time        `json:"released_at,omitempty"`
+	Events        []AssignmentEvent `json:"events"`
+	User          *User             `json:"user,omitempty"` // 관리자 사물함별 조회에서만 (탈퇴/병합으로 없어진 계정이면 null)
+}
+
+// AssignmentHistoryResponse 배정 히스토리 한 페이지 (최신순)
+type AssignmentHistoryResponse struct {
+	Assignments []AssignmentHistoryItem `json:"assignments"`
+	NextCursor  *string                 `json:"next_cursor" example:"120"` // 다음 페이지 cursor (마지막 페이지면 null)
+}
+
+// events: 배정 행의 시각 컬럼으로 타임라인 구성
+// - 만료(expired)는 released_at을 남기지 않으므로 hold_expires_at을 만료 시각으로 쓴다.
+func (it AssignmentHistoryItem) events() []AssignmentEvent {
+	out := []AssignmentEvent{}
+	if it.HoldExpiresAt == nil && it.ConfirmedAt != nil {
+		out = append(out, AssignmentEvent{Type: HistoryClaim, At: it.CreatedAt})
+	} else {
+		out = append(out, AssignmentEvent{Type: HistoryHold, At: it.CreatedAt})
+		if it.ConfirmedAt != nil {
+			out = append(out, AssignmentEvent{Type: HistoryConfirm, At: *it.ConfirmedAt})
+		}
+	}
+	switch it.State {
+	case "expired":
+		at := it.CreatedAt
+		if it.HoldExpiresAt != nil {
+			at = *it.HoldExpiresAt
+		}
+		out = append(out, AssignmentEvent{Type: HistoryExpire, At: at})
+	case "cancelled":
+		typ := HistoryRelease
+		if it.ConfirmedAt == nil {
+			typ = HistoryHoldRelease
+		}
+		at := it.CreatedAt
+		if it.ReleasedAt != nil {
+			at = *it.ReleasedAt
+		}
+		out = append(out, AssignmentEvent{Type: typ, At: at})
+	}
+	return out
+}
+
+// parseHistoryPage: cursor(이전 응답의 next_cursor)와 limit
+func parseHistoryPage(c *fiber.Ctx) (cursor *int64, limit int, err error) {
+	limit = defaultHistoryPageSize
+	if raw := c.Query("cursor"); raw != "" {
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 1 {
+			return nil, 0, fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+		}
+		cursor = &v
+	}
+	if raw := c.Query("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			return nil, 0, fiber.NewError(fiber.StatusBadRequest, "invalid limit")
+		}
+		limit = min(v, maxHistoryPageSize)
+	}
+	return cursor, limit, nil
+}
+
+// queryHistory: byColumn(user_serial_id | locker_id) = id 인 배정을 최신순으로 limit개
+// - withUser면 배정한 사용자 정보도 채운다 (관리자용)
+func queryHistory(c *fiber.Ctx, d Deps, byColumn string, id int64, cursor *int64, limit int, withUser bool) (AssignmentHistoryResponse, error) {
+	out := AssignmentHistoryResponse{Assignments: []AssignmentHistoryItem{}}
+	rows, err := d.DB.Query(c.Context(),
+		`SELECT a.assignment_id, a.locker_id, ll.name, a.state::text, a.created_at::timestamptz,
+		        a.hold_expires_at::timestamptz, a.confirmed_at::timestamptz, a.released_at::timestamptz,
+		        u.serial_id, u.student_id, u.name, u.phone_number
+		   FROM locker_assignments a
+		   JOIN locker_info l ON l.locker_id = a.locker_id
+		   JOIN locker_locations ll ON ll.location_id = l.location_id
+		   LEFT JOIN users u ON u.serial_id = a.user_serial_id AND u.deleted_at IS NULL
+		  WHERE a.`+byColumn+` = $1
+		    AND ($2::bigint IS NULL OR a.assignment_id < $2)
+		  ORDER BY a.assignment_id DESC
+		  LIMIT $3`,
+		id, cursor, limit)
+	if err != nil {
+		return out, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			it                           AssignmentHistoryItem
+			holdExp, confirmed, released sql.NullTime
+			userSerial                   sql.NullInt64
+			studentID, name, phone       sql.NullString
+		)
+		if err := rows.Scan(&it.AssignmentID, &it.LockerID, &it.LocationName, &it.State, &it.CreatedAt,
+			&holdExp, &confirmed, &released, &userSerial, &studentID, &name, &phone); err != nil {
+			return out, err
+		}
+		it.HoldExpiresAt = nullTimePtr(holdExp)
+		it.ConfirmedAt = nullTimePtr(confirmed)
+		it.ReleasedAt = nullTimePtr(released)
+		it.Events = it.events()
+		if withUser && userSerial.Valid {
+			it.User = &User{SerialID: userSerial.Int64, StudentID: studentID.String, Name: name.String, PhoneNumber: phone.String}
+		}
+		out.Assignments = append(out.Assignments, it)
+	}
+	if err := rows.Err(); err != nil {
+		return out, err
+	}
+
+	if len(out.Assignments) == limit {
+		next := strconv.FormatInt(out.Assignments[len(out.Assignments)-1].AssignmentID, 10)
+		out.NextCursor = &next
+	}
+	return out, nil
+}
+
+// GetMyLockerHistory godoc
+// @Summary      내 사물함 배정 히스토리
+// @Description  현재 사용자의 사물함 배정 이력(hold, 만료, 확정, 해제)을 위치 이름, 시각과 함께 최신순으로 반환합니다. 각 배정의 events는 시간 순 타임라인입니다. 응답의 next_cursor를 cursor로 넘기면 다음 페이지입니다.
+// @Tags         lockers
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        cursor query int false "이전 응답의 next_cursor"
+// @Param        limit query int false "페이지 크기 (최대 200)" default(50)
+// @Success      200 {object} AssignmentHistoryResponse
+// @Failure      400 {object} ErrorResponse "invalid cursor / invalid limit"
+// @Failure      401 {object} ErrorResponse "인증 필요 - JWT 토큰이 없거나 유효하지 않음"
+// @Failure      500 {object} ErrorResponse "서버 오류"
+// @Router       /lockers/me/history [get]
+func GetMyLockerHistory(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+		cursor, limit, err := parseHistoryPage(c)
+		if err != nil {
+			return err
+		}
+
+		out, err := queryHistory(c, d, "user_serial_id", serialID, cursor, limit, false)
+		if err != nil {
+			log.Printf("GetMyLockerHistory: query failed for user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.JSON(out)
+	}
+}
+
+// GetLockerHistory godoc
+// @Summary      사물함별 배정 히스토리 (관리자)
+// @Description  사물함 한 개의 배정 이력(누가 언제 hold/확정/해제했고 언제 만료됐는지)을 사용자 정보와 함께 최신순으로 반환합니다. 응답의 next_cursor를 cursor로 넘기면 다음 페이지입니다. 요청 단위의 상세 기록(IP 등)은 GET /admin/audit?locker_id= 로 봅니다.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        id path int true "사물함 ID" example(101)
+// @Param        cursor query int false "이전 응답의 next_cursor"
+// @Param        limit query int false "페이지 크기 (최대 200)" default(50)
+// @Success      200 {object} AssignmentHistoryResponse
+// @Failure      400 {object} ErrorResponse "invalid cursor / invalid limit"
+// @Failure      401 {object} ErrorResponse "unauthorized"
+// @Failure      403 {object} ErrorResponse "admin only"
+// @Failure      404 {object} ErrorResponse "locker not found"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /admin/lockers/{id}/history [get]
+func GetLockerHistory(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil || id < 1 {
+			return fiber.ErrBadRequest
+		}
+		cursor, limit, err := parseHistoryPage(c)
+		if err != nil {
+			return err
+		}
+
+		out, err := queryHistory(c, d, "locker_id", int64(id), cursor, limit, true)
+		if err != nil {
+			log.Printf("GetLockerHistory: query failed for locker %d: %v", id, err)
+			return fiber.ErrInternalServerError
+		}
+		// 이력이 없으면 사물함 자체가 없는지 확인
+		if len(out.Assignments) == 0 && cursor == nil {
+			var exists bool
+			if err := d.DB.QueryRow(c.Context(),
+				`SELECT EXISTS (SELECT 1 FROM locker_info WHERE locker_id = $1)`, id).Scan(&exists); err != nil {
+				return fiber.ErrInternalServerError
+			}
+			if !exists {
+				return fiber.NewError(fiber.StatusNotFound, "locker not found")
+			}
+		}
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.JSON(out)
+	}
+}
@@ -0,0 +1,429 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// 계정(프로필) 관리
+// - LoginOrRegister는 (student_id, name, phone_number)로 upsert 하므로, 번호가 바뀐 뒤 그냥 로그인하면 새 계정이 생긴다.
+// - 그래서 이름/전화번호 변경은 로그인된 상태에서 PATCH /auth/me로 같은 serial_id 행을 직접 수정한다.
+// - 탈퇴(DELETE /auth/me)는 행을 지우지 않고 개인정보만 익명화한다 (히스토리 보존).
+
+// UpdateMeRequest 프로필 수정 요청
+// - current_phone_number: 본인 확인용 (현재 등록된 번호와 일치해야 함)
+// - name / phone_number: 바꿀 값만 보낸다
+type UpdateMeRequest struct {
+	CurrentPhone string  `json:"current_phone_number" example:"01012345678"`
+	Name         *string `json:"name,omitempty" example:"홍길동"`
+	Phone        *string `json:"phone_number,omitempty" example:"01098765432"`
+}
+
+// DeleteMeRequest 탈퇴 요청 (본인 확인용 현재 전화번호)
+type DeleteMeRequest struct {
+	CurrentPhone string `json:"current_phone_number" example:"01012345678"`
+}
+
+// ExportUser 내보내기: 사용자 정보
+type ExportUser struct {
+	SerialID  int64     `json:"serial_id" example:"123456789012"`
+	StudentID string    `json:"student_id" example:"2025320000"`
+	Name      string    `json:"name" example:"홍길동"`
+	Phone     string    `json:"phone_number" example:"01012345678"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExportAssignment 내보내기: 사물함 배정 히스토리 한 건
+type ExportAssignment struct {
+	AssignmentID  int64      `json:"assignment_id" example:"1"`
+	LockerID      int        `json:"locker_id" example:"101"`
+	LocationName  string     `json:"location_name" example:"정보관 B1 엘리베이터"`
+	State         string     `json:"state" example:"confirmed"`
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty"`
+	ConfirmedAt   *time.Time `json:"confirmed_at,omitempty"`
+	ReleasedAt    *time.Time `json:"released_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ExportSession 내보내기: 세션(로그인 기록) 한 건
+type ExportSession struct {
+	ID         int64      `json:"id" example:"42"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip" example:"203.0.113.10"`
+	IssuedAt   time.Time  `json:"issued_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// ExportMeResponse 내 데이터 전체 내보내기
+type ExportMeResponse struct {
+	ExportedAt  time.Time          `json:"exported_at"`
+	User        ExportUser         `json:"user"`
+	Assignments []ExportAssignment `json:"assignments"`
+	Sessions    []ExportSession    `json:"sessions"`
+}
+
+// UpdateMe godoc
+// @Summary      내 프로필 수정
+// @Description  현재 전화번호로 본인 확인 후 이름/전화번호를 변경합니다. serial_id는 그대로 유지되므로 이후 새 정보로 로그인해도 같은 계정입니다.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        payload body UpdateMeRequest true "변경할 정보"
+// @Success      200 {object} GetMeResponse
+// @Failure      400 {object} ErrorResponse "nothing to update / invalid phone_number format / invalid name length"
+// @Failure      401 {object} ErrorResponse "unauthorized - invalid or missing token"
+// @Failure      403 {object} ErrorResponse "current_phone_number does not match"
+// @Failure      409 {object} ErrorResponse "another account already uses this student_id, name and phone_number"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /auth/me [patch]
+func UpdateMe(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+
+		var req UpdateMeRequest
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.ErrBadRequest
+		}
+		req.CurrentPhone = strings.TrimSpace(req.CurrentPhone)
+		if req.CurrentPhone == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "missing required field: current_phone_number")
+		}
+		if req.Name == nil && req.Phone == nil {
+			return fiber.NewError(fiber.StatusBadRequest, "nothing to update")
+		}
+
+		// 새 값 검증 (LoginOrRegister와 같은 규칙)
+		var newName, newPhone *string
+		if req.Name != nil {
+			v := strings.TrimSpace(*req.Name)
+			if err := validateName(v); err != nil {
+				return err
+			}
+			newName = &v
+		}
+		if req.Phone != nil {
+			v := strings.TrimSpace(*req.Phone)
+			if err := validatePhone(v); err != nil {
+				return err
+			}
+			newPhone = &v
+		}
+
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		// 본인 확인(현재 번호 일치) + 수정을 한 문장으로 처리 (어떤 항목이 바뀌었는지 알기 위해 이전 값도 반환)
+		var (
+			out               GetMeResponse
+			oldName, oldPhone string
+		)
+		err = tx.QueryRow(c.Context(),
+			`UPDATE users u
+			    SET name = COALESCE($3, u.name),
+			        phone_number = COALESCE($4, u.phone_number),
+			        updated_at = now()
+			   FROM users prev
+			  WHERE u.serial_id = $1 AND u.phone_number = $2 AND u.deleted_at IS NULL
+			    AND prev.serial_id = u.serial_id
+			RETURNING u.student_id, u.name, u.phone_number, prev.name, prev.phone_number`,
+			serialID, req.CurrentPhone, newName, newPhone,
+		).Scan(&out.StudentID, &out.Name, &out.Phone, &oldName, &oldPhone)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fiber.NewError(fiber.StatusForbidden, "current_phone_number does not match")
+			}
+			// ux_users_ident(student_id, name, phone_number) 충돌: 바꾸려는 정보로 이미 다른 계정이 존재
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return fiber.NewError(fiber.StatusConflict, "another account already uses this student_id, name and phone_number")
+			}
+			log.Printf("UpdateMe: failed to update user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+
+		// 감사 로그는 지우지 않으므로 값 자체는 남기지 않고 바뀐 항목 이름만 기록 (탈퇴 시 익명화가 무력화되지 않도록)
+		changed := []string{}
+		if out.Name != oldName {
+			changed = append(changed, "name")
+		}
+		if out.Phone != oldPhone {
+			changed = append(changed, "phone_number")
+		}
+		if err := audit.Record(c.Context(), tx, requestAudit(c).On(audit.UserUpdate, audit.TargetUser, strconv.FormatInt(serialID, 10)).
+			Change(nil, map[string]any{"changed": changed})); err != nil {
+			log.Printf("UpdateMe: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		log.Printf("UpdateMe: profile updated for user %d", serialID)
+		return c.JSON(out)
+	}
+}
+
+// ExportMe godoc
+// @Summary      내 데이터 내보내기
+// @Description  현재 사용자의 계정 정보, 사물함 배정 히스토리, 세션(로그인) 기록 전체를 JSON 파일로 내려줍니다.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Success      200 {object} ExportMeResponse
+// @Failure      401 {object} ErrorResponse "unauthorized - invalid or missing token"
+// @Failure      404 {object} ErrorResponse "user not found"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /auth/me/export [get]
+func ExportMe(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+
+		out := ExportMeResponse{
+			ExportedAt:  time.Now(),
+			Assignments: []ExportAssignment{},
+			Sessions:    []ExportSession{},
+		}
+
+		// 1) 사용자 정보
+		err := d.DB.QueryRow(c.Context(),
+			`SELECT serial_id, student_id, name, phone_number, created_at::timestamptz, updated_at::timestamptz
+			   FROM users WHERE serial_id = $1 AND deleted_at IS NULL`,
+			serialID,
+		).Scan(&out.User.SerialID, &out.User.StudentID, &out.User.Name, &out.User.Phone, &out.User.CreatedAt, &out.User.UpdatedAt)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "user not found")
+			}
+			log.Printf("ExportMe: failed to query user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+
+		// 2) 사물함 배정 히스토리
+		rows, err := d.DB.Query(c.Context(),
+			`SELECT a.assignment_id, a.locker_id, ll.name, a.state::text,
+			        a.hold_expires_at::timestamptz, a.confirmed_at::timestamptz, a.released_at::timestamptz, a.created_at::timestamptz
+			   FROM locker_assignments a
+			   JOIN locker_info l ON l.locker_id = a.locker_id
+			   JOIN locker_locations ll ON ll.location_id = l.location_id
+			  WHERE a.user_serial_id = $1
+			  ORDER BY a.created_at, a.assignment_id`,
+			serialID)
+		if err != nil {
+			log.Printf("ExportMe: failed to query assignments for user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+		for rows.Next() {
+			var (
+				it                            ExportAssignment
+				holdExp, confirmed, releasedT sql.NullTime
+			)
+			if err := rows.Scan(&it.AssignmentID, &it.LockerID, &it.LocationName, &it.State,
+				&holdExp, &confirmed, &releasedT, &it.CreatedAt); err != nil {
+				rows.Close()
+				return fiber.ErrInternalServerError
+			}
+			it.HoldExpiresAt = nullTimePtr(holdExp)
+			it.ConfirmedAt = nullTimePtr(confirmed)
+			it.ReleasedAt = nullTimePtr(releasedT)
+			out.Assignments = append(out.Assignments, it)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		// 3) 세션(로그인) 기록 - 토큰 해시/jti는 내보내지 않음
+		rows, err = d.DB.Query(c.Context(),
+			`SELECT id, COALESCE(user_agent, ''), COALESCE(ip, ''), issued_at::timestamptz,
+			        last_used_at::timestamptz, expires_at::timestamptz, revoked_at::timestamptz
+			   FROM auth_refresh_tokens
+			  WHERE user_serial_id = $1
+			  ORDER BY issued_at, id`,
+			serialID)
+		if err != nil {
+			log.Printf("ExportMe: failed to query sessions for user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				s                 ExportSession
+				lastUsed, revoked sql.NullTime
+			)
+			if err := rows.Scan(&s.ID, &s.UserAgent, &s.IP, &s.IssuedAt, &lastUsed, &s.ExpiresAt, &revoked); err != nil {
+				return fiber.ErrInternalServerError
+			}
+			s.LastUsedAt = nullTimePtr(lastUsed)
+			s.RevokedAt = nullTimePtr(revoked)
+			out.Sessions = append(out.Sessions, s)
+		}
+		if err := rows.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		// 브라우저에서 바로 파일로 저장되도록
+		c.Set(fiber.HeaderContentDisposition,
+			`attachment; filename="locker-export-`+strconv.FormatInt(serialID, 10)+`.json"`)
+		return c.JSON(out)
+	}
+}
+
+// DeleteMe godoc
+// @Summary      회원 탈퇴 (익명화)
+// @Description  현재 전화번호로 본인 확인 후 계정을 익명화합니다. 보유 중인 hold/사물함은 해제되고 모든 세션이 무효화됩니다. 사물함 배정 히스토리는 감사 목적으로 보존됩니다.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        payload body DeleteMeRequest true "본인 확인 정보"
+// @Success      200 {object} SimpleSuccessResponse
+// @Failure      400 {object} ErrorResponse "missing required field: current_phone_number"
+// @Failure      401 {object} ErrorResponse "unauthorized - invalid or missing token"
+// @Failure      403 {object} ErrorResponse "current_phone_number does not match"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /auth/me [delete]
+func DeleteMe(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+
+		var req DeleteMeRequest
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.ErrBadRequest
+		}
+		req.CurrentPhone = strings.TrimSpace(req.CurrentPhone)
+		if req.CurrentPhone == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "missing required field: current_phone_number")
+		}
+
+		tx, err := d.DB.Begin(c.Context())
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		defer tx.Rollback(c.Context())
+
+		// 1) 본인 확인 + 익명화
+		//    - ux_users_ident(student_id, name, phone_number) 충돌을 피하려고 phone_number에 serial_id를 넣는다.
+		//    - student_id는 10자리 숫자 형식이 아니므로 이후 이 행으로는 로그인할 수 없다.
+		ct, err := tx.Exec(c.Context(),
+			`UPDATE users
+			    SET student_id = 'deleted',
+			        name = '탈퇴한 사용자',
+			        phone_number = 'deleted-' || serial_id::text,
+			        deleted_at = now(),
+			        updated_at = now()
+			  WHERE serial_id = $1 AND phone_number = $2 AND deleted_at IS NULL`,
+			serialID, req.CurrentPhone)
+		if err != nil {
+			log.Printf("DeleteMe: failed to anonymize user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+		if ct.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusForbidden, "current_phone_number does not match")
+		}
+
+		// 2) 활성 hold/confirmed 해제 (히스토리 행은 남긴다)
+		rows, err := tx.Query(c.Context(),
+			`UPDATE locker_assignments
+			    SET state = 'cancelled', released_at = now()
+			  WHERE user_serial_id = $1 AND state IN ('hold', 'confirmed')
+			RETURNING locker_id`,
+			serialID)
+		if err != nil {
+			log.Printf("DeleteMe: failed to cancel assignments for user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+		var releasedLockers []int
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err == nil {
+				releasedLockers = append(releasedLockers, id)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		// 3) 소유 중인 사물함 비우기
+		if _, err := tx.Exec(c.Context(),
+			`UPDATE locker_info SET owner_serial_id = NULL, owner_student_id = NULL WHERE owner_serial_id = $1`,
+			serialID); err != nil {
+			log.Printf("DeleteMe: failed to clear locker ownership for user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+
+		if err := audit.Record(c.Context(), tx, requestAudit(c).On(audit.UserDelete, audit.TargetUser, strconv.FormatInt(serialID, 10)).
+			Change(nil, map[string]any{"anonymized": true, "released_lockers": releasedLockers})); err != nil {
+			log.Printf("DeleteMe: %v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		for _, id := range releasedLockers {
+			d.Lockers.Publish(c.Context(), id, lockercache.EventRelease)
+		}
+
+		// 4) 모든 세션 revoke + access token 블랙리스트 (현재 토큰 포함)
+		if jti, _ := c.Locals("jti").(string); jti != "" {
+			blacklistAccessJTI(c.Context(), d.RDB, jti)
+		}
+		if _, err := revokeSessions(c.Context(), d, requestAudit(c).On(audit.UserDelete, "", ""),
+			`UPDATE auth_refresh_tokens
+			    SET revoked_at = now()
+			  WHERE user_serial_id = $1 AND revoked_at IS NULL
+			RETURNING id, access_jti`,
+			serialID); err != nil {
+			log.Printf("DeleteMe: failed to revoke sessions for user %d: %v", serialID, err)
+		}
+
+		// 5) 남아있을지 모르는 내 hold 키 제거 (베스트 에포트, 소유자 확인)
+		for _, id := range releasedLockers {
+			_ = d.Holds.Release(c.Context(), id, serialID)
+		}
+		if util.CookieTransport() {
+			clearAuthCookies(c)
+		}
+
+		log.Printf("DeleteMe: user %d anonymized (released %d lockers)", serialID, len(releasedLockers))
+		return c.JSON(SimpleSuccessResponse{Message: "account deleted successfully"})
+	}
+}
+
+// nullTimePtr: sql.NullTime → *time.Time (NULL이면 nil)
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
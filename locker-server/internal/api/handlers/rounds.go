@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/eligibility"
+	"github.com/KUCSEPotato/locker-server/internal/round"
+	"github.com/gofiber/fiber/v2"
+)
+
+// 현재 회차 일정 (학생 본인 기준)
+// - 우선 순위 그룹이 있으면 학생마다 신청 시작 시각이 다르므로, 프론트는 이 값으로 카운트다운을 띄운다.
+// - 기기 시계가 틀려도 카운트다운이 맞도록 /rounds/current/clock으로 서버 시각과의 차이를 잰다.
+
+// PriorityGroupSummary 회차의 우선 순위 그룹 한 개
+type PriorityGroupSummary struct {
+	GroupID int64     `json:"group_id" example:"1"`
+	Name    string    `json:"name" example:"4학년"`
+	OpensAt time.Time `json:"opens_at"`
+	Member  bool      `json:"member" example:"true"` // 호출자가 이 그룹 조건을 만족하는지
+}
+
+// MyRoundScheduleResponse 호출자의 현재 회차 일정
+type MyRoundScheduleResponse struct {
+	RoundID        int64                  `json:"round_id" example:"3"` // 0이면 환경변수 기본 회차
+	RoundName      string                 `json:"round_name" example:"2025-1 정기 신청"`
+	Eligible       bool                   `json:"eligible" example:"true"`
+	Ineligible     *eligibility.Decision  `json:"ineligible,omitempty"` // 자격이 없을 때 사유
+	Group          *PriorityGroupSummary  `json:"group,omitempty"`      // 적용된 우선 순위 그룹 (없으면 일반)
+	OpensAt        *time.Time             `json:"opens_at"`             // 호출자의 신청 시작 (null이면 제한 없음)
+	ClosesAt       *time.Time             `json:"closes_at"`            // 신청 마감 (null이면 제한 없음)
+	GeneralOpensAt *time.Time             `json:"general_opens_at"`     // 그룹이 없는 학생의 신청 시작
+	Open           bool                   `json:"open" example:"false"` // 지금 hold할 수 있는지 (자격 + 기간)
+	ServerTime     time.Time              `json:"server_time"`
+	Groups         []PriorityGroupSummary `json:"groups"`
+}
+
+// RoundClockResponse 서버 시각 + 회차 일정 (NTP 방식 시계 보정용)
+// - 시각(t0~t2)은 Unix epoch 밀리초(마이크로초까지 소수)
+// - 클라이언트는 요청 직전 t0, 응답 받은 직후 t3을 잰다.
+// - offset = ((t1 - t0) + (t2 - t3)) / 2 (서버 시각 - 기기 시각), delay = (t3 - t0) - (t2 - t1)
+type RoundClockResponse struct {
+	T0             *float64   `json:"t0,omitempty" example:"1740877198001.5"` // 요청의 t0 그대로 (보내지 않았으면 생략)
+	T1             float64    `json:"t1" example:"1740877198021.25"`          // 서버가 요청을 받은 시각
+	T2             float64    `json:"t2" example:"1740877198023.75"`          // 서버가 응답을 보낸 시각
+	ServerTime     time.Time  `json:"server_time"`                            // t2와 같은 시각 (RFC3339, 나노초)
+	RoundID        int64      `json:"round_id" example:"3"`
+	OpensAt        *time.Time `json:"opens_at"`                      // 호출자의 신청 시작 (우선 순위 그룹 반영)
+	ClosesAt       *time.Time `json:"closes_at"`                     // 신청 마감
+	GeneralOpensAt *time.Time `json:"general_opens_at"`              // 그룹이 없는 학생의 신청 시작
+	Group          string     `json:"group,omitempty" example:"4학년"` // 적용된 우선 순위 그룹
+}
+
+// GetRoundClock godoc
+// @Summary      서버 시각 + 신청 시작 카운트다운
+// @Description  서버 시각(마이크로초 정밀도)과 현재 회차의 신청 시작/마감, 호출자의 신청 시작 시각을 반환합니다. t0(요청 직전 기기 시각, Unix ms)을 보내면 그대로 돌려주므로, 응답을 받은 시각 t3과 함께 offset = ((t1 - t0) + (t2 - t3)) / 2 로 기기 시계 오차를 추정할 수 있습니다. 여러 번 재서 delay가 가장 작은 값을 쓰는 것을 권장합니다.
+// @Tags         rounds
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        t0 query number false "요청 직전 기기 시각 (Unix epoch 밀리초, 소수 허용)"
+// @Success      200 {object} RoundClockResponse
+// @Failure      400 {object} ErrorResponse "invalid t0"
+// @Failure      401 {object} ErrorResponse "인증 필요 - JWT 토큰이 없거나 유효하지 않음"
+// @Failure      500 {object} ErrorResponse "서버 오류"
+// @Router       /rounds/current/clock [get]
+func GetRoundClock(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// t1: fasthttp가 요청을 받기 시작한 시각 (미들웨어 처리 시간 제외)
+		t1 := c.Context().Time()
+
+		var t0 *float64
+		if raw := c.Query("t0"); raw != "" {
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || v <= 0 {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid t0: must be unix epoch milliseconds")
+			}
+			t0 = &v
+		}
+
+		rd, _, sc, err := callerSchedule(c, d)
+		if err != nil {
+			return err
+		}
+		resp := RoundClockResponse{
+			T0:             t0,
+			T1:             unixMillis(t1),
+			RoundID:        rd.ID,
+			OpensAt:        sc.OpensAt,
+			ClosesAt:       sc.ClosesAt,
+			GeneralOpensAt: rd.OpensAt,
+		}
+		if sc.Group != nil {
+			resp.Group = sc.Group.Name
+		}
+
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		// t2: 응답 직전
+		t2 := time.Now()
+		resp.T2, resp.ServerTime = unixMillis(t2), t2
+		return c.JSON(resp)
+	}
+}
+
+// unixMillis: Unix epoch 밀리초 (마이크로초까지 소수)
+func unixMillis(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1000
+}
+
+// callerSchedule: 현재 회차 + 호출자 학생 정보 + 호출자 일정
+func callerSchedule(c *fiber.Ctx, d Deps) (*round.Round, eligibility.Student, round.Schedule, error) {
+	studentID, _ := c.Locals("student_id").(string)
+	if studentID == "" {
+		return nil, eligibility.Student{}, round.Schedule{}, fiber.ErrUnauthorized
+	}
+	rd, err := round.Current(c.Context(), d.DB)
+	if err != nil {
+		return nil, eligibility.Student{}, round.Schedule{}, fiber.ErrInternalServerError
+	}
+	st, err := lookupStudent(c, d, rd, studentID)
+	if err != nil {
+		return nil, eligibility.Student{}, round.Schedule{}, err
+	}
+	return rd, st, rd.ScheduleFor(st), nil
+}
+
+// GetMyRoundSchedule godoc
+// @Summary      내 신청 일정 조회
+// @Description  현재 회차에서 호출자의 신청 자격, 적용되는 우선 순위 그룹, 신청 시작/마감 시각을 반환합니다. 우선 순위 그룹이 있으면 조건을 만족하는 그룹 중 가장 이른 시작 시각이 적용됩니다.
+// @Tags         rounds
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Success      200 {object} MyRoundScheduleResponse
+// @Failure      401 {object} ErrorResponse "인증 필요 - JWT 토큰이 없거나 유효하지 않음"
+// @Failure      500 {object} ErrorResponse "서버 오류"
+// @Router       /rounds/current/me [get]
+func GetMyRoundSchedule(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rd, st, sc, err := callerSchedule(c, d)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		dec := eligibility.Decide(rd.Eligibility, st, "")
+		resp := MyRoundScheduleResponse{
+			RoundID:        rd.ID,
+			RoundName:      rd.Name,
+			Eligible:       dec.Eligible,
+			OpensAt:        sc.OpensAt,
+			ClosesAt:       sc.ClosesAt,
+			GeneralOpensAt: rd.OpensAt,
+			Open:           dec.Eligible && !sc.NotOpenYet(now) && !sc.Closed(now),
+			ServerTime:     now,
+			Groups:         []PriorityGroupSummary{},
+		}
+		if !dec.Eligible {
+			resp.Ineligible = &dec
+		}
+		for _, g := range rd.Groups {
+			gs := PriorityGroupSummary{GroupID: g.ID, Name: g.Name, OpensAt: g.OpensAt, Member: g.Rules.Match(st, "").Eligible}
+			if sc.Group != nil && sc.Group.ID == g.ID {
+				resp.Group = &gs
+			}
+			resp.Groups = append(resp.Groups, gs)
+		}
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.JSON(resp)
+	}
+}
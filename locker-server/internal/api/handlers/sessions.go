@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// 세션 = auth_refresh_tokens의 한 행
+// - 로그인 시 생성되고, refresh 시에는 같은 행에서 토큰만 교체(rotate)된다.
+// - access token의 sid 클레임이 이 행의 id를 가리킨다.
+
+// SessionResponse 세션(로그인된 디바이스) 한 건
+type SessionResponse struct {
+	ID         int64           `json:"id" example:"42"`
+	Device     util.DeviceInfo `json:"device"`
+	UserAgent  string          `json:"user_agent" example:"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) ..."`
+	IP         string          `json:"ip" example:"203.0.113.10"`
+	IssuedAt   time.Time       `json:"issued_at"`
+	LastUsedAt *time.Time      `json:"last_used_at,omitempty"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+	Current    bool            `json:"current" example:"true"` // 지금 요청을 보낸 세션인지
+}
+
+// ListSessionsResponse 내 활성 세션 목록
+type ListSessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+	Count    int               `json:"count" example:"2"`
+}
+
+// RevokeSessionsResponse 세션 revoke 결과
+type RevokeSessionsResponse struct {
+	Message string `json:"message" example:"sessions revoked successfully"`
+	Revoked int64  `json:"revoked" example:"3"`
+}
+
+// ListSessions godoc
+// @Summary      내 세션(디바이스) 목록
+// @Description  현재 사용자의 활성(만료/회수되지 않은) 세션 목록을 User-Agent 기반 기기 정보와 함께 반환합니다.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Success      200 {object} ListSessionsResponse
+// @Failure      401 {object} ErrorResponse "unauthorized - invalid or missing token"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /auth/sessions [get]
+func ListSessions(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+		currentSID, _ := c.Locals("session_id").(int64)
+
+		// timestamp without time zone 컬럼은 DB 세션 타임존 기준으로 기록되므로 timestamptz로 캐스팅해서 읽는다.
+		rows, err := d.DB.Query(c.Context(),
+			`SELECT id, COALESCE(user_agent, ''), COALESCE(ip, ''),
+			        issued_at::timestamptz, last_used_at::timestamptz, expires_at::timestamptz
+			   FROM auth_refresh_tokens
+			  WHERE user_serial_id = $1
+			    AND revoked_at IS NULL
+			    AND now() < expires_at
+			  ORDER BY COALESCE(last_used_at, issued_at) DESC`,
+			serialID)
+		if err != nil {
+			log.Printf("ListSessions: query failed for user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+		defer rows.Close()
+
+		out := []SessionResponse{}
+		for rows.Next() {
+			var (
+				s        SessionResponse
+				lastUsed sql.NullTime
+			)
+			if err := rows.Scan(&s.ID, &s.UserAgent, &s.IP, &s.IssuedAt, &lastUsed, &s.ExpiresAt); err != nil {
+				return fiber.ErrInternalServerError
+			}
+			if lastUsed.Valid {
+				t := lastUsed.Time
+				s.LastUsedAt = &t
+			}
+			s.Device = util.ParseUserAgent(s.UserAgent)
+			s.Current = currentSID != 0 && s.ID == currentSID
+			out = append(out, s)
+		}
+		if err := rows.Err(); err != nil {
+			return fiber.ErrInternalServerError
+		}
+
+		return c.JSON(ListSessionsResponse{Sessions: out, Count: len(out)})
+	}
+}
+
+// RevokeSession godoc
+// @Summary      세션 하나 revoke
+// @Description  지정한 세션의 Refresh Token을 무효화하고, 그 세션으로 발급된 Access Token도 블랙리스트에 올립니다.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Param        id path int true "세션 ID"
+// @Success      200 {object} RevokeSessionsResponse
+// @Failure      400 {object} ErrorResponse "invalid session id"
+// @Failure      401 {object} ErrorResponse "unauthorized - invalid or missing token"
+// @Failure      404 {object} ErrorResponse "session not found"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /auth/sessions/{id} [delete]
+func RevokeSession(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+		sid, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || sid <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid session id")
+		}
+
+		// 본인 세션만 revoke 가능 (남의 세션 id는 404로 동일 취급)
+		n, err := revokeSessions(c.Context(), d, requestAudit(c).On(audit.SessionRevoke, "", ""),
+			`UPDATE auth_refresh_tokens
+			    SET revoked_at = now()
+			  WHERE id = $1 AND user_serial_id = $2 AND revoked_at IS NULL
+			RETURNING id, access_jti`,
+			sid, serialID)
+		if err != nil {
+			log.Printf("RevokeSession: failed to revoke session %d for user %d: %v", sid, serialID, err)
+			return fiber.ErrInternalServerError
+		}
+		if n == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "session not found")
+		}
+
+		return c.JSON(RevokeSessionsResponse{Message: "session revoked successfully", Revoked: n})
+	}
+}
+
+// RevokeOtherSessions godoc
+// @Summary      다른 디바이스 모두 로그아웃
+// @Description  지금 요청을 보낸 세션을 제외한 모든 세션을 무효화하고, 해당 세션들의 Access Token을 블랙리스트에 올립니다.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Success      200 {object} RevokeSessionsResponse
+// @Failure      401 {object} ErrorResponse "unauthorized - invalid or missing token"
+// @Failure      500 {object} ErrorResponse "internal server error"
+// @Router       /auth/sessions/revoke-others [post]
+func RevokeOtherSessions(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+		// sid 클레임이 없는 (구버전) 토큰이면 0 → 모든 세션이 "다른 세션"으로 처리됨
+		currentSID, _ := c.Locals("session_id").(int64)
+
+		n, err := revokeSessions(c.Context(), d, requestAudit(c).On(audit.SessionRevokeOthers, "", ""),
+			`UPDATE auth_refresh_tokens
+			    SET revoked_at = now()
+			  WHERE user_serial_id = $1 AND id <> $2 AND revoked_at IS NULL
+			RETURNING id, access_jti`,
+			serialID, currentSID)
+		if err != nil {
+			log.Printf("RevokeOtherSessions: failed for user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+		log.Printf("RevokeOtherSessions: revoked %d sessions for user %d", n, serialID)
+
+		return c.JSON(RevokeSessionsResponse{Message: "other sessions revoked successfully", Revoked: n})
+	}
+}
+
+// LogoutAll 핸들러: 해당 사용자의 모든 세션(모든 디바이스)을 무효화
+// LogoutAll godoc
+// @Summary      전체 로그아웃 (모든 디바이스)
+// @Description  현재 사용자의 모든 Refresh Token을 무효화하고, 각 세션의 Access Token과 현재 Access Token을 블랙리스트에 올립니다.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer {access_token}" default(Bearer )
+// @Success      200 {object} RevokeSessionsResponse
+// @Failure      401 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /auth/logout-all [post]
+func LogoutAll(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+
+		// 1) 현재 Access Token 블랙리스트 (세션에 기록된 jti와 같겠지만 구버전 토큰 대비)
+		if jti, _ := c.Locals("jti").(string); jti != "" {
+			blacklistAccessJTI(c.Context(), d.RDB, jti)
+		}
+
+		// 2) 모든 세션 revoke + 각 세션의 access jti 블랙리스트
+		n, err := revokeSessions(c.Context(), d, requestAudit(c).On(audit.AuthLogoutAll, "", ""),
+			`UPDATE auth_refresh_tokens
+			    SET revoked_at = now()
+			  WHERE user_serial_id = $1 AND revoked_at IS NULL
+			RETURNING id, access_jti`,
+			serialID)
+		if err != nil {
+			log.Printf("LogoutAll: failed to revoke sessions for user %d: %v", serialID, err)
+			return fiber.ErrInternalServerError
+		}
+		log.Printf("Revoked %d refresh tokens for user %d (logout-all)", n, serialID)
+
+		if util.CookieTransport() {
+			clearAuthCookies(c)
+		}
+
+		return c.JSON(RevokeSessionsResponse{Message: "logged out from all devices successfully", Revoked: n})
+	}
+}
+
+// ───────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ───────────────────────────────────────────────────────────────────────────────
+
+// issueSession: 새 세션(refresh 토큰 행)을 만들고 그 세션에 묶인 access token을 발급
+// - 반환: (access token, refresh 평문). refresh 평문은 이 한 번만 클라이언트에 전달된다.
+// - 세션 생성과 감사 로그(action: auth.login / auth.register)는 한 트랜잭션
+func issueSession(c *fiber.Ctx, d Deps, serialID int64, studentID, action string) (string, string, error) {
+	refreshPlain := util.RandomToken(32)
+	refreshHash := sha256.Sum256([]byte(refreshPlain))
+	hashB64 := base64.RawURLEncoding.EncodeToString(refreshHash[:])
+
+	userAgent := string(c.Request().Header.UserAgent())
+	refreshExpires := time.Now().Add(time.Hour * time.Duration(util.EnvInt("JWT_REFRESH_TTL_H", 336)))
+
+	tx, err := d.DB.Begin(c.Context())
+	if err != nil {
+		return "", "", err
+	}
+	defer tx.Rollback(c.Context())
+
+	// 세션 행 생성 → id를 access token의 sid로 사용
+	var sid int64
+	err = tx.QueryRow(c.Context(), `
+		INSERT INTO auth_refresh_tokens (user_serial_id, token_hash, expires_at, user_agent, ip)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, serialID, hashB64, refreshExpires, userAgent, clientIP(c)).Scan(&sid)
+	if err != nil {
+		return "", "", err
+	}
+
+	accessToken, jti, err := util.IssueAccessToken(serialID, studentID, sid)
+	if err != nil {
+		return "", "", err
+	}
+
+	// 세션 revoke 시 블랙리스트에 올릴 수 있도록 jti 기록
+	if _, err := tx.Exec(c.Context(),
+		`UPDATE auth_refresh_tokens SET access_jti = $1 WHERE id = $2`, jti, sid,
+	); err != nil {
+		return "", "", err
+	}
+
+	ev := requestAudit(c)
+	ev.ActorSerialID = serialID // 로그인 요청에는 아직 JWT가 없다
+	if err := audit.Record(c.Context(), tx, ev.On(action, audit.TargetSession, strconv.FormatInt(sid, 10)).
+		Change(nil, map[string]any{"session_id": sid, "user_serial_id": serialID, "expires_at": refreshExpires})); err != nil {
+		return "", "", err
+	}
+	if err := tx.Commit(c.Context()); err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshPlain, nil
+}
+
+// revokeSessions: "UPDATE ... RETURNING id, access_jti" 쿼리를 실행하고
+// revoke된 세션마다 감사 로그(ev.Action, 대상 session)를 같은 트랜잭션에 남긴 뒤
+// 커밋되면 반환된 jti들을 블랙리스트에 올리고 revoke된 세션 수를 돌려준다.
+func revokeSessions(ctx context.Context, d Deps, ev audit.Event, query string, args ...any) (int64, error) {
+	tx, err := d.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		sids []int64
+		jtis []string
+	)
+	for rows.Next() {
+		var (
+			sid int64
+			jti sql.NullString
+		)
+		if err := rows.Scan(&sid, &jti); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		sids = append(sids, sid)
+		if jti.Valid && jti.String != "" {
+			jtis = append(jtis, jti.String)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, sid := range sids {
+		if err := audit.Record(ctx, tx, ev.On(ev.Action, audit.TargetSession, strconv.FormatInt(sid, 10)).
+			Change(map[string]any{"revoked": false}, map[string]any{"revoked": true})); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	for _, jti := range jtis {
+		blacklistAccessJTI(ctx, d.RDB, jti)
+	}
+	return int64(len(sids)), nil
+}
+
+// blacklistAccessJTI: access token의 jti를 블랙리스트에 등록 (베스트 에포트)
+// - TTL은 access token 최대 수명과 같게 잡아서 만료 후 자동 정리되도록 한다.
+func blacklistAccessJTI(ctx context.Context, rdb *redis.Client, jti string) {
+	jti = strings.TrimSpace(jti)
+	if jti == "" {
+		return
+	}
+	ttlMin := util.EnvInt("JWT_ACCESS_TTL_MIN", 10)
+	if err := rdb.Set(ctx, "blacklist:"+jti, "revoked", time.Duration(ttlMin)*time.Minute).Err(); err != nil {
+		log.Printf("Failed to blacklist access token %s: %v", jti, err)
+	}
+}
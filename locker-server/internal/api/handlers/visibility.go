@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 사물함 응답의 필드 단위 공개 범위
+// - 점유 여부(status)는 모두에게 공개
+// - 소유자 식별 정보(owner, owner_serial_id)는 그 사물함의 소유자 본인과 관리자에게만 공개
+// - 목록/내 사물함/관리자 화면 모두 LockerResponse를 만든 뒤 viewer.apply를 거쳐서 내보낸다.
+
+// viewer 응답을 받는 사용자
+type viewer struct {
+	serialID int64
+	admin    bool
+}
+
+// viewerFrom: JWTAuth(+ WithRole/AdminOnly)가 넣어 둔 Locals에서 viewer 구성
+func viewerFrom(c *fiber.Ctx) viewer {
+	serialID, _ := c.Locals("user_serial_id").(int64)
+	admin, _ := c.Locals("is_admin").(bool)
+	return viewer{serialID: serialID, admin: admin}
+}
+
+// canSeeOwner: 이 사용자가 해당 사물함의 소유자 정보를 볼 수 있는지
+func (v viewer) canSeeOwner(ownerSerialID *int64) bool {
+	if v.admin {
+		return true
+	}
+	return ownerSerialID != nil && v.serialID != 0 && *ownerSerialID == v.serialID
+}
+
+// apply: 공개 범위 밖의 필드를 지운 응답
+func (v viewer) apply(it LockerResponse) LockerResponse {
+	if !v.canSeeOwner(it.OwnerSerialID) {
+		it.Owner = nil
+		it.OwnerSerialID = nil
+	}
+	return it
+}
+
+// etag: 스냅샷 ETag를 사용자별로 구분 (공개 범위에 따라 본문이 달라지므로)
+// - 관리자는 모두 같은 본문, 일반 사용자는 본인 사물함만 소유자 정보가 보이므로 사용자별로 다르다.
+func (v viewer) etag(base string) string {
+	suffix := "-u" + strconv.FormatInt(v.serialID, 10)
+	if v.admin {
+		suffix = "-a"
+	}
+	return strings.TrimSuffix(base, `"`) + suffix + `"`
+}
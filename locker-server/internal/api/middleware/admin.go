@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+// AdminOnly 는 JWTAuth 뒤에 붙여서 관리자 전용 라우트를 보호하는 미들웨어로,
+// 1) JWTAuth가 넣어둔 user_serial_id로 users.is_admin 조회
+// 2) 관리자가 아니면 403
+// 3) 관리자면 c.Locals("is_admin")=true 로 표시 후 통과
+// - 권한은 토큰이 아니라 매 요청 DB에서 확인하므로, 권한 회수가 즉시 반영된다.
+func AdminOnly(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+
+		isAdmin, err := lookupAdmin(c, d, serialID)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		if !isAdmin {
+			return fiber.NewError(fiber.StatusForbidden, "admin only")
+		}
+
+		c.Locals("is_admin", true)
+		return c.Next()
+	}
+}
+
+// WithRole 은 JWTAuth 뒤에 붙여서 c.Locals("is_admin")에 관리자 여부만 표시하는 미들웨어 (거절하지 않음)
+// - 일반 사용자와 관리자가 같은 라우트를 쓰되 응답 필드가 달라지는 경우(예: 사물함 목록의 소유자 정보)에 사용
+func WithRole(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 {
+			return fiber.ErrUnauthorized
+		}
+
+		isAdmin, err := lookupAdmin(c, d, serialID)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		c.Locals("is_admin", isAdmin)
+		return c.Next()
+	}
+}
+
+// lookupAdmin: users.is_admin 조회 (탈퇴했거나 없는 사용자는 false)
+func lookupAdmin(c *fiber.Ctx, d Deps, serialID int64) (bool, error) {
+	var isAdmin bool
+	err := d.DB.QueryRow(c.Context(),
+		`SELECT is_admin FROM users WHERE serial_id = $1 AND deleted_at IS NULL`,
+		serialID,
+	).Scan(&isAdmin)
+	if err != nil && err != pgx.ErrNoRows {
+		log.Printf("AdminOnly: failed to check admin role for user %d: %v", serialID, err)
+		return false, err
+	}
+	return isAdmin, nil
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/KUCSEPotato/locker-server/internal/cache"
 	"github.com/KUCSEPotato/locker-server/internal/util"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
@@ -15,15 +16,16 @@ import (
 
 // Deps: 미들웨어에서 사용할 의존성
 type Deps struct {
-	DB  *pgxpool.Pool
-	RDB *redis.Client
+	DB          *pgxpool.Pool
+	RDB         *redis.Client
+	RedisHealth *cache.Monitor // Redis 장애 시 블랙리스트 대신 DB로 세션 확인 / 쓰기 라우트 503
 }
 
 // JWTAuth 는 보호된 라우트에서 사용되는 미들웨어로,
-// 1) Authorization 헤더에 Bearer 토큰이 있는지 확인
+// 1) Authorization 헤더에 Bearer 토큰이 있는지 확인 (쿠키 모드면 access_token 쿠키도 허용)
 // 2) 토큰 서명/클레임(iss, aud, exp 등) 검증
-// 3) 블랙리스트 체크
-// 4) sub(학번)를 c.Locals("student_id")에 저장해 핸들러에서 사용 가능하게 함
+// 3) 블랙리스트 체크 (Redis 장애 중에는 Postgres의 세션 revoke 여부로 대신 확인)
+// 4) sub(serial_id)/student_id/sid/jti를 c.Locals에 저장해 핸들러에서 사용 가능하게 함
 func JWTAuth(d Deps) fiber.Handler {
 	// 환경변수로부터 검증에 필요한 값 로드
 	secret := []byte(os.Getenv("JWT_ACCESS_SECRET"))
@@ -38,15 +40,23 @@ func JWTAuth(d Deps) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// HTTP Authorization 헤더에서 Bearer 토큰 추출
 		// 예) "Authorization: Bearer eyJhbGciOi..."
+		// 헤더가 없으면 쿠키 모드에서만 access_token 쿠키를 사용
+		var tokenStr string
 		authz := c.Get("Authorization")
-		if !strings.HasPrefix(authz, "Bearer ") {
+		switch {
+		case strings.HasPrefix(authz, "Bearer "):
+			tokenStr = strings.TrimPrefix(authz, "Bearer ")
+		case authz == "" && util.CookieTransport() && c.Cookies(util.AccessTokenCookie) != "":
+			tokenStr = c.Cookies(util.AccessTokenCookie)
+		default:
 			// 토큰이 없거나 포맷이 잘못되면 401
 			return fiber.ErrUnauthorized
 		}
-		tokenStr := strings.TrimPrefix(authz, "Bearer ")
 
 		// 블랙리스트 체크 (먼저 체크해서 불필요한 파싱 방지)
-		if jti, err := util.ExtractJTI(tokenStr); err == nil {
+		// - Redis 장애(degraded mode) 중에는 아래에서 클레임을 읽은 뒤 Postgres로 확인한다 (checkSessionDB)
+		redisUp := d.RedisHealth.Up()
+		if jti, err := util.ExtractJTI(tokenStr); err == nil && redisUp {
 			blacklistKey := "blacklist:" + jti
 			if exists, _ := d.RDB.Exists(c.Context(), blacklistKey).Result(); exists > 0 {
 				log.Printf("Token is blacklisted: %s", jti)
@@ -96,7 +106,54 @@ func JWTAuth(d Deps) fiber.Handler {
 		studentID, _ := claims["student_id"].(string)
 		c.Locals("student_id", studentID)
 
+		// sid(세션 ID = auth_refresh_tokens.id)와 jti는 세션 관리 API에서 사용.
+		// sid가 없는 구버전 토큰도 허용 (세션 관련 기능만 제한됨)
+		if sidStr, _ := claims["sid"].(string); sidStr != "" {
+			if sid, err := strconv.ParseInt(sidStr, 10, 64); err == nil {
+				c.Locals("session_id", sid)
+			}
+		}
+		jti, _ := claims["jti"].(string)
+		c.Locals("jti", jti)
+
+		if !redisUp {
+			if err := checkSessionDB(c, d, serialID, jti); err != nil {
+				return err
+			}
+		}
+
 		// 다음 미들웨어/핸들러 실행
 		return c.Next()
 	}
 }
+
+// checkSessionDB: Redis 블랙리스트를 볼 수 없을 때 토큰의 세션(sid)이 아직 유효한지 Postgres로 확인
+// - logout / logout-all / 세션 revoke / 탈퇴는 revoked_at, refresh는 access_jti, 계정 병합은 user_serial_id를 바꾼다.
+// - 그래서 세션이 revoke되지 않았고, 같은 사용자이며, 마지막으로 발급된 jti일 때만 통과 (블랙리스트와 같은 기준)
+// - sid가 없는 구버전 토큰은 확인할 방법이 없으므로 조회(GET/HEAD)만 허용하고 나머지는 503
+// - DB 오류도 통과시키지 않는다 (503)
+func checkSessionDB(c *fiber.Ctx, d Deps, serialID int64, jti string) error {
+	sid, _ := c.Locals("session_id").(int64)
+	if sid == 0 {
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			return nil
+		}
+		return fiber.NewError(fiber.StatusServiceUnavailable, "cannot verify token revocation right now, please retry shortly")
+	}
+
+	var active bool
+	err := d.DB.QueryRow(c.Context(),
+		`SELECT EXISTS (SELECT 1 FROM auth_refresh_tokens
+		                 WHERE id = $1 AND user_serial_id = $2 AND revoked_at IS NULL
+		                   AND COALESCE(access_jti, $3) = $3)`,
+		sid, serialID, jti).Scan(&active)
+	if err != nil {
+		log.Printf("JWTAuth: session check for sid %d failed: %v", sid, err)
+		return fiber.NewError(fiber.StatusServiceUnavailable, "cannot verify token revocation right now, please retry shortly")
+	}
+	if !active {
+		log.Printf("Token session revoked (db check): sid=%d", sid)
+		return fiber.ErrUnauthorized
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"log"
+
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CSRF 는 쿠키 전송 모드에서 사용하는 double-submit CSRF 검증 미들웨어로,
+// 1) 쿠키 모드가 아니거나 안전한 메서드(GET/HEAD/OPTIONS)면 그대로 통과
+// 2) Authorization 헤더로 인증하는 요청은 브라우저가 자동 첨부한 것이 아니므로 통과
+// 3) 인증 쿠키(access/refresh)가 실린 요청은 csrf_token 쿠키와 X-CSRF-Token 헤더가 일치해야 함
+func CSRF() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !util.CookieTransport() {
+			return c.Next()
+		}
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+		if c.Get("Authorization") != "" {
+			return c.Next()
+		}
+		// 인증 쿠키가 하나도 없으면 CSRF로 얻을 권한이 없음 → 뒤 핸들러가 401 처리
+		if c.Cookies(util.AccessTokenCookie) == "" && c.Cookies(util.RefreshTokenCookie) == "" {
+			return c.Next()
+		}
+
+		cookieToken := c.Cookies(util.CSRFTokenCookie)
+		headerToken := c.Get(util.CSRFHeader)
+		if cookieToken == "" || headerToken == "" ||
+			subtle.ConstantTimeCompare([]byte(cookieToken), []byte(headerToken)) != 1 {
+			log.Printf("CSRF token mismatch: %s %s", c.Method(), c.Path())
+			return fiber.NewError(fiber.StatusForbidden, "invalid csrf token")
+		}
+		return c.Next()
+	}
+}
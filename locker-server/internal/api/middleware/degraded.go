@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRedis 는 Redis가 꼭 필요한 라우트(hold/confirm/claim 등)에 붙이는 미들웨어로,
+// 1) cache.Monitor가 Redis 장애로 판단한 상태면 Redis에 붙어 보지 않고 바로 503 (+ Retry-After)
+// 2) 정상이면 통과
+// - 조회 API(ListLockers, GetMyLocker 등)에는 붙이지 않으므로 Redis 장애 중에도 계속 동작한다 (degraded mode)
+func RequireRedis(d Deps) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !d.RedisHealth.Up() {
+			c.Set(fiber.HeaderRetryAfter, "5")
+			return fiber.NewError(fiber.StatusServiceUnavailable, ErrMsgRedisDegraded)
+		}
+		return c.Next()
+	}
+}
+
+// ErrMsgRedisDegraded Redis 장애(읽기 전용 모드) 시 쓰기 요청에 돌려주는 메시지
+const ErrMsgRedisDegraded = "locker reservations are temporarily unavailable (read-only mode: redis is down), please retry shortly"
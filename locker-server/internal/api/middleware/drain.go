@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/KUCSEPotato/locker-server/internal/lifecycle"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Drain 은 종료 시 끝까지 처리해야 하는 요청(hold/confirm 등)에 붙이는 미들웨어로,
+// 1) 요청 시작/종료를 InFlight에 기록 → 종료 시 이 요청들이 끝날 때까지 기다릴 수 있음
+// 2) 이미 종료(drain) 중이면 새 요청은 503으로 거절 (클라이언트는 다른 인스턴스로 재시도)
+func Drain(inflight *lifecycle.InFlight) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !inflight.Begin() {
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return fiber.NewError(fiber.StatusServiceUnavailable, "server is shutting down")
+		}
+		defer inflight.Done()
+		return c.Next()
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+// IdempotencyKeyHeader 클라이언트가 재시도마다 같은 값을 보내는 헤더
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader 저장된 응답을 재생했을 때 붙이는 응답 헤더
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// 처리 중 표시(processing)의 최대 유지 시간. 핸들러가 이보다 오래 걸리는 일은 없다고 본다.
+const idempotencyLockTTL = 30 * time.Second
+
+// idempotencyRecord Redis에 저장하는 요청/응답 기록
+type idempotencyRecord struct {
+	Done        bool   `json:"done"`
+	Hash        string `json:"hash"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Idempotency 는 JWTAuth 뒤에 붙여서 상태 변경 요청의 재시도를 안전하게 만드는 미들웨어로,
+// 1) Idempotency-Key 헤더가 없으면 그대로 통과
+// 2) 키 = idem:{serial_id}:{Idempotency-Key}, 요청 해시 = sha256(method, path, body)
+// 3) 처음 보는 키면 처리 중으로 표시한 뒤 핸들러 실행 → 응답(상태/바디)을 IDEMPOTENCY_TTL_SEC(기본 86400초) 동안 저장
+// 4) 같은 키 + 같은 요청이면 저장된 응답을 그대로 재생 (Idempotent-Replayed: true)
+// 5) 같은 키 + 다른 요청이면 422, 아직 처리 중이면 409
+// - 5xx 응답은 저장하지 않고 키를 지워서 다시 시도할 수 있게 한다.
+// - Redis 장애 시에는 멱등성 없이 그대로 처리한다 (재시도 편의 기능이므로 요청 자체를 막지 않음).
+func Idempotency(d Deps) fiber.Handler {
+	ttl := time.Duration(util.EnvInt("IDEMPOTENCY_TTL_SEC", 86400)) * time.Second
+
+	return func(c *fiber.Ctx) error {
+		idemKey := c.Get(IdempotencyKeyHeader)
+		if idemKey == "" {
+			return c.Next()
+		}
+		if len(idemKey) > 255 {
+			return fiber.NewError(fiber.StatusBadRequest, "Idempotency-Key too long")
+		}
+		serialID, _ := c.Locals("user_serial_id").(int64)
+		if serialID == 0 || !d.RedisHealth.Up() {
+			return c.Next()
+		}
+
+		key := "idem:" + strconv.FormatInt(serialID, 10) + ":" + idemKey
+		sum := sha256.Sum256([]byte(c.Method() + "\n" + c.Path() + "\n" + string(c.Body())))
+		hash := hex.EncodeToString(sum[:])
+
+		// 처리 중 표시 (키가 없을 때만)
+		pending, _ := json.Marshal(idempotencyRecord{Hash: hash})
+		ok, err := d.RDB.SetNX(c.Context(), key, pending, idempotencyLockTTL).Result()
+		if err != nil {
+			log.Printf("Idempotency: redis unavailable, processing without key %q: %v", idemKey, err)
+			return c.Next()
+		}
+		if !ok {
+			return replayIdempotent(c, d, key, hash)
+		}
+
+		// 핸들러 실행. 에러는 여기서 응답으로 만들어야 상태/바디를 저장할 수 있다.
+		if err := c.Next(); err != nil {
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = d.RDB.Del(c.Context(), key).Err()
+				return herr
+			}
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			_ = d.RDB.Del(c.Context(), key).Err()
+			return nil
+		}
+		done, _ := json.Marshal(idempotencyRecord{
+			Done:        true,
+			Hash:        hash,
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        c.Response().Body(),
+		})
+		if err := d.RDB.Set(c.Context(), key, done, ttl).Err(); err != nil {
+			log.Printf("Idempotency: failed to store response for key %q: %v", idemKey, err)
+		}
+		return nil
+	}
+}
+
+// replayIdempotent: 이미 본 키 → 저장된 응답 재생 / 충돌 응답
+func replayIdempotent(c *fiber.Ctx, d Deps, key, hash string) error {
+	raw, err := d.RDB.Get(c.Context(), key).Bytes()
+	if err != nil {
+		// 그 사이 만료/삭제됐을 수 있음 → 클라이언트가 다시 시도하도록
+		return fiber.NewError(fiber.StatusConflict, "request with this Idempotency-Key is in progress, retry later")
+	}
+	var rec idempotencyRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if rec.Hash != hash {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+	}
+	if !rec.Done {
+		return fiber.NewError(fiber.StatusConflict, "request with this Idempotency-Key is in progress, retry later")
+	}
+
+	c.Set(IdempotentReplayedHeader, "true")
+	if rec.ContentType != "" {
+		c.Set(fiber.HeaderContentType, rec.ContentType)
+	}
+	return c.Status(rec.Status).Send(rec.Body)
+}
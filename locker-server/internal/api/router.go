@@ -20,13 +20,17 @@ func Setup(app *fiber.App, deps handlers.Deps) {
 	// --- 인증(로그인/리프레시) 엔드포인트는 공개(public) ---
 	// v1.Post("/auth/register", handlers.Register(deps))                 // 회원가입
 	// v1.Post("/auth/login", handlers.Login(deps))                       // 학번/이름/폰번호 확인 → 토큰 발급
-	v1.Post("/auth/refresh", handlers.Refresh(deps))                   // 리프레시 토큰으로 액세스 갱신
-	v1.Post("/auth/logout", handlers.Logout(deps))                     // 로그아웃 (토큰 무효화)
+	// 쿠키 전송 모드(AUTH_TOKEN_TRANSPORT=cookie)에서는 refresh 쿠키를 쓰는 요청에 CSRF 토큰 필요
+	csrf := middleware.CSRF()
+	v1.Post("/auth/refresh", csrf, handlers.Refresh(deps))             // 리프레시 토큰으로 액세스 갱신
+	v1.Post("/auth/logout", csrf, handlers.Logout(deps))               // 로그아웃 (토큰 무효화)
 	v1.Post("/auth/login-or-register", handlers.LoginOrRegister(deps)) // 로그인 또는 자동 회원가입
 
 	// [250904] 추가: 헬스 체크 엔드포인트
 	// --- 헬스 체크 엔드포인트 ---
-	v1.Get("/health", handlers.HealthCheck(deps.DB, deps.RDB)) // DB, Redis 상태 확인
+	v1.Get("/health", handlers.HealthReady(deps))       // 기존 경로 호환 (= /health/ready)
+	v1.Get("/health/live", handlers.HealthLive())       // 프로세스 생존 확인 (liveness)
+	v1.Get("/health/ready", handlers.HealthReady(deps)) // 의존성별 상태/지연 시간 (readiness, degraded mode 포함)
 
 	// [250909] 추가: 모든 유저 조회 (관리자용)
 	// --- 모든 유저 조회 (관리자용) ---
@@ -36,19 +40,65 @@ func Setup(app *fiber.App, deps handlers.Deps) {
 	// 빈 prefix("")에 JWT 미들웨어를 덧씌워서 같은 그룹 안 라우트에 공통적용
 	// 미들웨어에서 블랙리스트 체크를 위해 deps 전달
 	middlewareDeps := middleware.Deps{
-		DB:  deps.DB,
-		RDB: deps.RDB,
+		DB:          deps.DB,
+		RDB:         deps.RDB,
+		RedisHealth: deps.RedisHealth,
 	}
-	authed := v1.Group("", middleware.JWTAuth(middlewareDeps))
-
-	authed.Get("/lockers", handlers.ListLockers(deps))                   // 사물함 목록 조회
-	authed.Get("/lockers/me", handlers.GetMyLocker(deps))                // <-- 추가
-	authed.Post("/lockers/:id/hold", handlers.HoldLocker(deps))          // 사물함 홀드(선점)
-	authed.Post("/lockers/:id/confirm", handlers.ConfirmLocker(deps))    // 확정
-	authed.Post("/lockers/:id/release", handlers.ReleaseLocker(deps))    // 해제
-	authed.Post("/lockers/:id/release-hold", handlers.ReleaseHold(deps)) // HOLD 해제
-	authed.Get("/auth/me", handlers.GetMe(deps))                         // 현재 로그인된 사용자 정보 조회
-	// authed.Post("/auth/logout-all", handlers.LogoutAll(deps))            // 전체 로그아웃 (모든 디바이스)
+	// 쿠키로 인증된 상태 변경 요청(POST/PUT/PATCH/DELETE)은 CSRF 토큰도 검사
+	authed := v1.Group("", middleware.JWTAuth(middlewareDeps), csrf)
+
+	authed.Get("/lockers", middleware.WithRole(middlewareDeps), handlers.ListLockers(deps)) // 사물함 목록 조회 (관리자는 소유자 정보 포함)
+	authed.Get("/lockers/me", handlers.GetMyLocker(deps))                                   // <-- 추가
+	authed.Get("/lockers/me/history", handlers.GetMyLockerHistory(deps))                    // 내 사물함 배정 히스토리 (cursor 페이지네이션)
+	authed.Get("/locations", handlers.ListLocations(deps))                                  // 위치별 사물함 수 요약 + 배치도
+	authed.Get("/locations/:id", handlers.GetLocation(deps))                                // 위치 하나 (배치도 포함)
+	authed.Get("/locations/:id/map.svg", handlers.GetLocationMap(deps))                     // 배치도 SVG (실시간 상태 색)
+	authed.Get("/rounds/current/me", handlers.GetMyRoundSchedule(deps))                     // 현재 회차 내 신청 일정 (자격 + 우선 순위 그룹)
+	authed.Get("/rounds/current/clock", handlers.GetRoundClock(deps))                       // 서버 시각(NTP 방식 t0~t2) + 내 신청 시작/마감
+
+	// 상태 변경 요청은 Idempotency-Key 헤더로 재시도 시 같은 응답을 재생 (약한 Wi-Fi에서 재전송 대비)
+	// 서버 종료 시에는 진행 중인 요청을 끝까지 처리하고(drain) 새 요청은 503으로 거절
+	// Redis 장애(degraded mode) 중에는 Redis가 필요한 요청을 바로 503으로 거절 (조회 API는 계속 동작)
+	drain := middleware.Drain(deps.InFlight)
+	redisUp := middleware.RequireRedis(middlewareDeps)
+	idem := middleware.Idempotency(middlewareDeps)
+	authed.Post("/lockers/:id/hold", drain, redisUp, idem, handlers.HoldLocker(deps))        // 사물함 홀드(선점)
+	authed.Post("/lockers/:id/hold/extend", drain, redisUp, idem, handlers.ExtendHold(deps)) // 홀드 연장 (회차 정책 횟수만큼)
+	authed.Post("/lockers/:id/confirm", drain, idem, handlers.ConfirmLocker(deps))           // 확정
+	authed.Post("/lockers/:id/claim", drain, redisUp, idem, handlers.ClaimLocker(deps))      // 바로 확정 (회차 direct_confirm 일 때)
+	authed.Post("/lockers/:id/release", drain, idem, handlers.ReleaseLocker(deps))           // 해제
+	authed.Post("/lockers/:id/release-hold", drain, idem, handlers.ReleaseHold(deps))        // HOLD 해제
+	authed.Get("/auth/me", handlers.GetMe(deps))                                             // 현재 로그인된 사용자 정보 조회
+	authed.Post("/auth/logout-all", handlers.LogoutAll(deps))                                // 전체 로그아웃 (모든 디바이스)
+
+	// --- 계정(프로필) 관리 ---
+	authed.Patch("/auth/me", handlers.UpdateMe(deps))      // 이름/전화번호 변경 (serial_id 유지)
+	authed.Get("/auth/me/export", handlers.ExportMe(deps)) // 내 데이터 JSON 내보내기
+	authed.Delete("/auth/me", handlers.DeleteMe(deps))     // 탈퇴 (익명화, 히스토리 보존)
+
+	// --- 세션(디바이스) 관리 ---
+	authed.Get("/auth/sessions", handlers.ListSessions(deps))                       // 내 활성 세션 목록
+	authed.Post("/auth/sessions/revoke-others", handlers.RevokeOtherSessions(deps)) // 현재 세션 외 전부 revoke
+	authed.Delete("/auth/sessions/:id", handlers.RevokeSession(deps))               // 세션 하나 revoke
+
+	// --- 관리자 전용 API (users.is_admin = true) ---
+	admin := authed.Group("/admin", middleware.AdminOnly(middlewareDeps))
+	admin.Get("/users/duplicates", handlers.GetDuplicateUsers(deps))                       // 중복 계정 후보 리포트
+	admin.Post("/users/merge", handlers.MergeUsers(deps))                                  // 중복 계정 병합 (dry_run 지원)
+	admin.Post("/users/rekey-serials", handlers.RekeyLegacySerials(deps))                  // legacy serial_id 재발급 (dry_run 지원)
+	admin.Get("/reconcile", handlers.GetReconcileReport(deps))                             // hold/배정 정합성 검사 마지막 결과
+	admin.Post("/reconcile", handlers.RunReconcile(deps))                                  // 정합성 검사 즉시 실행 (repair 옵션)
+	admin.Patch("/lockers/:id/attributes", handlers.UpdateLockerAttributes(deps))          // 사물함 속성(크기/층/콘센트/사용 중지) 수정
+	admin.Get("/lockers/:id/history", handlers.GetLockerHistory(deps))                     // 사물함별 배정 히스토리 (사용자 정보 포함)
+	admin.Put("/locations/:id/layout", handlers.UpdateLocationLayout(deps))                // 위치 배치도(격자 + 사물함 칸) 교체
+	admin.Post("/import/:kind", handlers.ImportData(deps))                                 // 위치/사물함/명단 CSV 가져오기 (dry_run 지원)
+	admin.Get("/export/assignments", handlers.ExportAssignments(deps))                     // 배정 + 사용자 정보 CSV/XLSX 내보내기
+	admin.Get("/eligibility/exceptions", handlers.ListEligibilityExceptions(deps))         // 신청 자격 예외 목록
+	admin.Post("/eligibility/exceptions", handlers.GrantEligibilityException(deps))        // 신청 자격 예외 추가 (학생별, 회차별 또는 전체)
+	admin.Delete("/eligibility/exceptions/:id", handlers.RevokeEligibilityException(deps)) // 신청 자격 예외 삭제
+	admin.Get("/eligibility/check", handlers.CheckEligibility(deps))                       // 학번의 현재 회차 신청 자격 확인
+	admin.Get("/audit", handlers.ListAuditEvents(deps))                                    // 감사 로그 조회 (행위자/동작/대상/기간 필터, cursor 페이지네이션)
+	admin.Get("/audit/export", handlers.ExportAuditEvents(deps))                           // 감사 로그 CSV/XLSX 내보내기
 
 	// swagger
 	// app.Get("/swagger/*", fiberSwagger.WrapHandler)
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// 감사 로그 (audit_events, 017_audit_events.sql)
+// - 변경과 같은 트랜잭션(pgx.Tx)으로 Record를 호출한다 → 변경이 롤백되면 기록도 남지 않고, 커밋되면 반드시 남는다.
+// - before/after는 JSON으로 저장한다 (nil이면 NULL). 토큰 평문 같은 비밀 값은 넣지 않는다.
+// - 테이블은 append-only (UPDATE/DELETE 트리거로 거부).
+
+// 행위자 구분
+const (
+	RoleUser   = "user"
+	RoleAdmin  = "admin"
+	RoleSystem = "system" // 스케줄러, 정합성 검사, CLI 등
+)
+
+// 대상 종류
+const (
+	TargetLocker      = "locker"
+	TargetUser        = "user"
+	TargetSession     = "session"
+	TargetLocation    = "location"
+	TargetImport      = "import"
+	TargetEligibility = "eligibility_exception"
+)
+
+// 동작 이름 (대상.동작)
+const (
+	LockerHold             = "locker.hold"
+	LockerHoldExtend       = "locker.hold_extend"
+	LockerHoldExtendRevert = "locker.hold_extend_revert" // Redis 연장 실패로 DB 연장을 되돌림
+	LockerHoldRelease      = "locker.hold_release"
+	LockerHoldExpire       = "locker.hold_expire"
+	LockerConfirm          = "locker.confirm"
+	LockerClaim            = "locker.claim"
+	LockerRelease          = "locker.release"
+
+	AuthRegister        = "auth.register"
+	AuthLogin           = "auth.login"
+	AuthRefresh         = "auth.refresh"
+	AuthLogout          = "auth.logout"
+	AuthLogoutAll       = "auth.logout_all"
+	SessionRevoke       = "session.revoke"
+	SessionRevokeOthers = "session.revoke_others"
+
+	UserUpdate = "user.update"
+	UserDelete = "user.delete"
+
+	AdminUserMerge         = "admin.user_merge"
+	AdminSerialRekey       = "admin.serial_rekey"
+	AdminLockerAttributes  = "admin.locker_attributes"
+	AdminLocationLayout    = "admin.location_layout"
+	AdminImport            = "admin.import"
+	AdminEligibilityGrant  = "admin.eligibility_grant"
+	AdminEligibilityRevoke = "admin.eligibility_revoke"
+
+	ReconcileRepair = "reconcile.repair" // 정합성 검사 복구 (DB 변경분만)
+)
+
+// Event 감사 이벤트 한 건
+type Event struct {
+	ActorSerialID int64  // 0이면 NULL (시스템/익명)
+	ActorRole     string // RoleUser | RoleAdmin | RoleSystem
+	Action        string
+	TargetType    string
+	TargetID      string
+	Before        any // 변경 전 상태 (JSON, nil이면 NULL)
+	After         any // 변경 후 상태 (JSON, nil이면 NULL)
+	IP            string
+	UserAgent     string
+}
+
+// System: 시스템 행위자 이벤트 (스케줄러, 정합성 검사 등)
+func System(action, targetType, targetID string) Event {
+	return Event{ActorRole: RoleSystem, Action: action, TargetType: targetType, TargetID: targetID}
+}
+
+// On: 같은 행위자/요청 정보로 대상과 동작만 바꾼 이벤트
+func (e Event) On(action, targetType, targetID string) Event {
+	e.Action, e.TargetType, e.TargetID = action, targetType, targetID
+	e.Before, e.After = nil, nil
+	return e
+}
+
+// Change: before/after 설정
+func (e Event) Change(before, after any) Event {
+	e.Before, e.After = before, after
+	return e
+}
+
+// Execer pgx.Tx / *pgxpool.Pool 공통 (변경과 같은 트랜잭션을 넘긴다)
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Record: 이벤트 한 건 기록
+func Record(ctx context.Context, db Execer, e Event) error {
+	before, err := marshal(e.Before)
+	if err != nil {
+		return fmt.Errorf("audit: before state: %w", err)
+	}
+	after, err := marshal(e.After)
+	if err != nil {
+		return fmt.Errorf("audit: after state: %w", err)
+	}
+	role := e.ActorRole
+	if role == "" {
+		role = RoleSystem
+	}
+	_, err = db.Exec(ctx,
+		`INSERT INTO audit_events (actor_serial_id, actor_role, action, target_type, target_id,
+		                           before_state, after_state, ip, user_agent)
+		 VALUES (NULLIF($1::bigint, 0), $2, $3, $4, NULLIF($5, ''), $6, $7, NULLIF($8, ''), NULLIF($9, ''))`,
+		e.ActorSerialID, role, e.Action, e.TargetType, e.TargetID, before, after, e.IP, e.UserAgent)
+	if err != nil {
+		return fmt.Errorf("audit: record %s: %w", e.Action, err)
+	}
+	return nil
+}
+
+// marshal: nil → NULL, 그 외 JSON
+func marshal(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
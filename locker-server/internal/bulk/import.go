@@ -0,0 +1,543 @@
+package bulk
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// 학기 준비용 CSV 가져오기 (위치 / 사물함 / 신청 자격 명단)
+// - 첫 줄은 헤더 (열 이름, 대소문자 무시, 순서 자유). 모르는 열은 에러.
+// - 먼저 모든 행을 검증하고, 하나라도 틀리면 DB는 건드리지 않고 행별 에러만 보고한다.
+// - 검증을 통과하면 한 트랜잭션에서 행마다 savepoint를 두고 upsert → DB 에러(FK 등)도 행별로 보고한다.
+// - dry_run이거나 에러가 하나라도 있으면 롤백 (전부 반영되거나 전혀 반영되지 않음).
+//
+// 열 구성:
+// - locations: name(필수), grid_rows, grid_cols
+// - lockers  : locker_id(필수), location 또는 location_id(필수), size, row, near_outlet, out_of_service
+// - roster   : student_id(필수), name, department, note, fee_paid
+//
+// 빈 칸은 "값 없음"으로, 사물함 속성/위치 격자에서는 기존 값을 유지한다.
+
+// Kind 가져오기 대상
+type Kind string
+
+const (
+	KindLocations Kind = "locations"
+	KindLockers   Kind = "lockers"
+	KindRoster    Kind = "roster"
+)
+
+// ErrUnknownKind 지원하지 않는 대상
+var ErrUnknownKind = errors.New("unknown import kind: must be locations, lockers or roster")
+
+// ErrReplaceNotSupported replace는 명단(roster)만 지원
+var ErrReplaceNotSupported = errors.New("replace is only supported for roster imports")
+
+// ParseKind: 문자열 → Kind
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(strings.ToLower(strings.TrimSpace(s))); k {
+	case KindLocations, KindLockers, KindRoster:
+		return k, nil
+	}
+	return "", ErrUnknownKind
+}
+
+// Options 가져오기 옵션
+type Options struct {
+	DryRun  bool // 검증 + DB 반영까지 해 보고 롤백
+	Replace bool // (roster) 파일에 없는 학생은 명단에서 제거
+
+	// Audit 이 행위자로 감사 로그 한 건(대상 import/<kind>, after = 결과 요약)을 같은 트랜잭션에 남긴다 (nil이면 시스템)
+	Audit *audit.Event
+}
+
+// RowError 행 단위 에러 (Line은 CSV 파일의 줄 번호, 헤더가 1)
+type RowError struct {
+	Line    int    `json:"line" example:"3"`
+	Column  string `json:"column,omitempty" example:"locker_id"`
+	Message string `json:"message" example:"duplicate locker_id 101 (also on line 2)"`
+}
+
+// Report 가져오기 결과
+type Report struct {
+	Kind     Kind       `json:"kind" example:"lockers"`
+	DryRun   bool       `json:"dry_run"`
+	Applied  bool       `json:"applied"` // 실제로 커밋되었는지
+	Rows     int        `json:"rows" example:"120"`
+	Inserted int        `json:"inserted" example:"100"`
+	Updated  int        `json:"updated" example:"20"`
+	Removed  int        `json:"removed" example:"0"` // replace로 제거된 명단 행
+	Errors   []RowError `json:"errors"`
+}
+
+// OK: 에러 없이 끝났는지
+func (r *Report) OK() bool {
+	return len(r.Errors) == 0
+}
+
+func (r *Report) addError(line int, column, format string, args ...any) {
+	r.Errors = append(r.Errors, RowError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+// Import: CSV를 읽어 kind 테이블에 반영
+// - 반환 에러는 입출력/DB 연결 같은 실행 실패만. 데이터 문제는 Report.Errors로 보고한다.
+func Import(ctx context.Context, db *pgxpool.Pool, kind Kind, r io.Reader, opts Options) (*Report, error) {
+	if opts.Replace && kind != KindRoster {
+		return nil, ErrReplaceNotSupported
+	}
+	rep := &Report{Kind: kind, DryRun: opts.DryRun, Errors: []RowError{}}
+
+	var (
+		spec  tableSpec
+		items []item
+	)
+	switch kind {
+	case KindLocations:
+		spec = locationSpec
+	case KindLockers:
+		spec = lockerSpec
+	case KindRoster:
+		spec = rosterSpec
+	default:
+		return nil, ErrUnknownKind
+	}
+
+	rows, err := readCSV(r, spec, rep)
+	if err != nil {
+		return nil, err
+	}
+	rep.Rows = len(rows)
+	seen := map[string]int{}
+	for _, row := range rows {
+		it, ok := spec.parse(row, rep)
+		if !ok {
+			continue
+		}
+		if prev, dup := seen[it.key]; dup {
+			rep.addError(row.line, spec.keyColumn, "duplicate %s %s (also on line %d)", spec.keyColumn, it.key, prev)
+			continue
+		}
+		seen[it.key] = row.line
+		items = append(items, it)
+	}
+	if !rep.OK() {
+		return rep, nil
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if spec.prepare != nil {
+		if err := spec.prepare(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+	for _, it := range items {
+		sp, err := tx.Begin(ctx) // savepoint: 한 행의 DB 에러가 나머지 행을 막지 않도록
+		if err != nil {
+			return nil, err
+		}
+		inserted, err := it.apply(ctx, sp)
+		if err != nil {
+			_ = sp.Rollback(ctx)
+			rep.addError(it.line, "", "%s", dbErrorMessage(err))
+			continue
+		}
+		if err := sp.Commit(ctx); err != nil {
+			return nil, err
+		}
+		if inserted {
+			rep.Inserted++
+		} else {
+			rep.Updated++
+		}
+	}
+
+	if opts.Replace && rep.OK() {
+		keys := make([]string, 0, len(items))
+		for _, it := range items {
+			keys = append(keys, it.key)
+		}
+		tag, err := tx.Exec(ctx, `DELETE FROM eligible_students WHERE student_id <> ALL($1)`, keys)
+		if err != nil {
+			return nil, err
+		}
+		rep.Removed = int(tag.RowsAffected())
+	}
+
+	if opts.DryRun || !rep.OK() {
+		return rep, nil // defer Rollback
+	}
+	ev := audit.System("", "", "")
+	if opts.Audit != nil {
+		ev = *opts.Audit
+	}
+	summary := map[string]any{"rows": rep.Rows, "inserted": rep.Inserted, "updated": rep.Updated, "removed": rep.Removed, "replace": opts.Replace}
+	if err := audit.Record(ctx, tx, ev.On(audit.AdminImport, audit.TargetImport, string(kind)).Change(nil, summary)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	rep.Applied = true
+	return rep, nil
+}
+
+// ───────────────────────────────────────────────────────────────────────────────
+// CSV 읽기
+// ───────────────────────────────────────────────────────────────────────────────
+
+// csvRow 헤더 이름 → 값 (앞뒤 공백 제거)
+type csvRow struct {
+	line   int
+	fields map[string]string
+}
+
+func (r csvRow) get(col string) string {
+	return r.fields[col]
+}
+
+// item 검증을 통과한 한 행 (apply는 savepoint 안에서 실행, inserted=false면 갱신)
+type item struct {
+	line  int
+	key   string
+	apply func(ctx context.Context, tx pgx.Tx) (inserted bool, err error)
+}
+
+// tableSpec 대상별 열 구성과 행 처리
+type tableSpec struct {
+	required  [][]string // 각 묶음 중 하나 이상 있어야 함 (예: location 또는 location_id)
+	optional  []string
+	keyColumn string // 파일 안 중복 검사 기준 열
+	parse     func(row csvRow, rep *Report) (item, bool)
+	prepare   func(ctx context.Context, tx pgx.Tx) error // 행 처리 전에 한 번 (nil 가능)
+}
+
+func readCSV(r io.Reader, spec tableSpec, rep *Report) ([]csvRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		rep.addError(1, "", "empty file: a header row is required")
+		return nil, nil
+	}
+	if err != nil {
+		rep.addError(1, "", "invalid csv: %v", err)
+		return nil, nil
+	}
+
+	known := map[string]bool{}
+	for _, group := range spec.required {
+		for _, col := range group {
+			known[col] = true
+		}
+	}
+	for _, col := range spec.optional {
+		known[col] = true
+	}
+
+	cols := make([]string, len(header))
+	present := map[string]bool{}
+	for i, h := range header {
+		if i == 0 {
+			h = strings.TrimPrefix(h, "\ufeff") // Excel이 붙이는 UTF-8 BOM
+		}
+		col := strings.ToLower(strings.TrimSpace(h))
+		if !known[col] {
+			rep.addError(1, col, "unknown column %q", h)
+			continue
+		}
+		if present[col] {
+			rep.addError(1, col, "duplicate column %q", h)
+			continue
+		}
+		cols[i] = col
+		present[col] = true
+	}
+	for _, group := range spec.required {
+		found := false
+		for _, col := range group {
+			found = found || present[col]
+		}
+		if !found {
+			rep.addError(1, group[0], "missing required column %s", strings.Join(group, " or "))
+		}
+	}
+	if !rep.OK() {
+		return nil, nil
+	}
+
+	var rows []csvRow
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if errors.As(err, &perr) {
+				rep.addError(perr.Line, "", "invalid csv: %v", perr.Err)
+				continue
+			}
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		row := csvRow{line: line, fields: map[string]string{}}
+		empty := true
+		for i, v := range rec {
+			if i >= len(cols) {
+				if strings.TrimSpace(v) != "" {
+					rep.addError(line, "", "too many fields (%d, header has %d)", len(rec), len(cols))
+				}
+				continue
+			}
+			v = strings.TrimSpace(v)
+			row.fields[cols[i]] = v
+			empty = empty && v == ""
+		}
+		if empty {
+			continue // 빈 줄
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// dbErrorMessage: 행 단위로 보여 줄 DB 에러 메시지
+func dbErrorMessage(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if pgErr.Detail != "" {
+			return pgErr.Message + ": " + pgErr.Detail
+		}
+		return pgErr.Message
+	}
+	return err.Error()
+}
+
+// ───────────────────────────────────────────────────────────────────────────────
+// 값 파싱 (실패 시 rep에 에러 추가 후 ok=false)
+// ───────────────────────────────────────────────────────────────────────────────
+
+func parsePositiveInt(row csvRow, col string, rep *Report) (*int, bool) {
+	raw := row.get(col)
+	if raw == "" {
+		return nil, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 1 {
+		rep.addError(row.line, col, "%s must be a positive integer, got %q", col, raw)
+		return nil, false
+	}
+	return &v, true
+}
+
+func parseBool(row csvRow, col string, rep *Report) (*bool, bool) {
+	raw := strings.ToLower(row.get(col))
+	switch raw {
+	case "":
+		return nil, true
+	case "true", "t", "1", "y", "yes", "o":
+		v := true
+		return &v, true
+	case "false", "f", "0", "n", "no", "x":
+		v := false
+		return &v, true
+	}
+	rep.addError(row.line, col, "%s must be true or false, got %q", col, row.get(col))
+	return nil, false
+}
+
+func parseEnum(row csvRow, col string, allowed []string, rep *Report) (*string, bool) {
+	raw := strings.ToLower(row.get(col))
+	if raw == "" {
+		return nil, true
+	}
+	for _, a := range allowed {
+		if raw == a {
+			return &raw, true
+		}
+	}
+	rep.addError(row.line, col, "%s must be one of %s, got %q", col, strings.Join(allowed, ", "), row.get(col))
+	return nil, false
+}
+
+func optionalString(row csvRow, col string) *string {
+	if v := row.get(col); v != "" {
+		return &v
+	}
+	return nil
+}
+
+// ───────────────────────────────────────────────────────────────────────────────
+// 대상별 처리
+// ───────────────────────────────────────────────────────────────────────────────
+
+var locationSpec = tableSpec{
+	required:  [][]string{{"name"}},
+	optional:  []string{"grid_rows", "grid_cols"},
+	keyColumn: "name",
+	// 001_init.sql 시드가 location_id를 직접 넣어서 SERIAL 시퀀스가 뒤처져 있을 수 있음 → 새 행 INSERT 전에 맞춰 둔다
+	prepare: func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			`SELECT setval(pg_get_serial_sequence('locker_locations', 'location_id'),
+			               GREATEST((SELECT MAX(location_id) FROM locker_locations), 1))`)
+		return err
+	},
+	parse: func(row csvRow, rep *Report) (item, bool) {
+		name := row.get("name")
+		if name == "" {
+			rep.addError(row.line, "name", "name is required")
+			return item{}, false
+		}
+		rows, ok1 := parsePositiveInt(row, "grid_rows", rep)
+		cols, ok2 := parsePositiveInt(row, "grid_cols", rep)
+		if !ok1 || !ok2 {
+			return item{}, false
+		}
+		if (rows == nil) != (cols == nil) {
+			rep.addError(row.line, "grid_rows", "grid_rows and grid_cols must be given together")
+			return item{}, false
+		}
+		return item{line: row.line, key: name, apply: func(ctx context.Context, tx pgx.Tx) (inserted bool, err error) {
+			err = tx.QueryRow(ctx,
+				`INSERT INTO locker_locations (name, grid_rows, grid_cols) VALUES ($1, $2, $3)
+				 ON CONFLICT (name) DO UPDATE
+				    SET grid_rows = COALESCE(EXCLUDED.grid_rows, locker_locations.grid_rows),
+				        grid_cols = COALESCE(EXCLUDED.grid_cols, locker_locations.grid_cols)
+				 RETURNING (xmax = 0)`,
+				name, rows, cols).Scan(&inserted)
+			return inserted, err
+		}}, true
+	},
+}
+
+var lockerSpec = tableSpec{
+	required:  [][]string{{"locker_id"}, {"location", "location_id"}},
+	optional:  []string{"size", "row", "near_outlet", "out_of_service"},
+	keyColumn: "locker_id",
+	parse: func(row csvRow, rep *Report) (item, bool) {
+		lockerID, ok := parsePositiveInt(row, "locker_id", rep)
+		if !ok {
+			return item{}, false
+		}
+		if lockerID == nil {
+			rep.addError(row.line, "locker_id", "locker_id is required")
+			return item{}, false
+		}
+		locationName := row.get("location")
+		locationID, ok := parsePositiveInt(row, "location_id", rep)
+		if !ok {
+			return item{}, false
+		}
+		if (locationName == "") == (locationID == nil) {
+			rep.addError(row.line, "location", "exactly one of location or location_id is required")
+			return item{}, false
+		}
+
+		size, ok1 := parseEnum(row, "size", lockercache.Sizes, rep)
+		rowPos, ok2 := parseEnum(row, "row", lockercache.RowPositions, rep)
+		nearOutlet, ok3 := parseBool(row, "near_outlet", rep)
+		outOfService, ok4 := parseBool(row, "out_of_service", rep)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return item{}, false
+		}
+		hasAttributes := size != nil || rowPos != nil || nearOutlet != nil || outOfService != nil
+
+		return item{line: row.line, key: strconv.Itoa(*lockerID), apply: func(ctx context.Context, tx pgx.Tx) (inserted bool, err error) {
+			locID := locationID
+			if locID == nil {
+				var id int
+				err := tx.QueryRow(ctx, `SELECT location_id FROM locker_locations WHERE name = $1`, locationName).Scan(&id)
+				if errors.Is(err, pgx.ErrNoRows) {
+					return false, fmt.Errorf("unknown location %q (import locations first)", locationName)
+				}
+				if err != nil {
+					return false, err
+				}
+				locID = &id
+			}
+
+			// 위치가 바뀌면 이전 위치 배치도의 칸은 의미가 없으므로 비운다
+			err = tx.QueryRow(ctx,
+				`INSERT INTO locker_info (locker_id, location_id) VALUES ($1, $2)
+				 ON CONFLICT (locker_id) DO UPDATE
+				    SET location_id = EXCLUDED.location_id,
+				        grid_row = CASE WHEN locker_info.location_id = EXCLUDED.location_id THEN locker_info.grid_row END,
+				        grid_col = CASE WHEN locker_info.location_id = EXCLUDED.location_id THEN locker_info.grid_col END
+				 RETURNING (xmax = 0)`,
+				*lockerID, *locID).Scan(&inserted)
+			if err != nil || !hasAttributes {
+				return inserted, err
+			}
+
+			_, err = tx.Exec(ctx,
+				`INSERT INTO locker_attributes (locker_id, size, row_position, near_outlet, out_of_service)
+				 VALUES ($1, $2, $3, COALESCE($4, false), COALESCE($5, false))
+				 ON CONFLICT (locker_id) DO UPDATE
+				    SET size           = COALESCE($2, locker_attributes.size),
+				        row_position   = COALESCE($3, locker_attributes.row_position),
+				        near_outlet    = COALESCE($4, locker_attributes.near_outlet),
+				        out_of_service = COALESCE($5, locker_attributes.out_of_service),
+				        updated_at     = now()`,
+				*lockerID, size, rowPos, nearOutlet, outOfService)
+			return inserted, err
+		}}, true
+	},
+}
+
+// 학번 형식 (가입 시 검증과 같은 10자리 숫자)
+var studentIDPattern = regexp.MustCompile(`^\d{10}$`)
+
+var rosterSpec = tableSpec{
+	required:  [][]string{{"student_id"}},
+	optional:  []string{"name", "department", "note", "fee_paid"},
+	keyColumn: "student_id",
+	parse: func(row csvRow, rep *Report) (item, bool) {
+		studentID := row.get("student_id")
+		if !studentIDPattern.MatchString(studentID) {
+			rep.addError(row.line, "student_id", "student_id must be 10 digits, got %q", studentID)
+			return item{}, false
+		}
+		name, department, note := optionalString(row, "name"), optionalString(row, "department"), optionalString(row, "note")
+		if name != nil && len([]rune(*name)) > 100 {
+			rep.addError(row.line, "name", "name is too long")
+			return item{}, false
+		}
+		// 빈 칸이면 기존 납부 여부 유지 (새 행은 미납)
+		feePaid, ok := parseBool(row, "fee_paid", rep)
+		if !ok {
+			return item{}, false
+		}
+		return item{line: row.line, key: studentID, apply: func(ctx context.Context, tx pgx.Tx) (inserted bool, err error) {
+			err = tx.QueryRow(ctx,
+				`INSERT INTO eligible_students (student_id, name, department, note, fee_paid)
+				 VALUES ($1, $2, $3, $4, COALESCE($5, false))
+				 ON CONFLICT (student_id) DO UPDATE
+				    SET name = EXCLUDED.name, department = EXCLUDED.department, note = EXCLUDED.note,
+				        fee_paid = COALESCE($5, eligible_students.fee_paid),
+				        imported_at = now()
+				 RETURNING (xmax = 0)`,
+				studentID, name, department, note, feePaid).Scan(&inserted)
+			return inserted, err
+		}}, true
+	},
+}
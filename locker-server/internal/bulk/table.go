@@ -0,0 +1,197 @@
+package bulk
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 표 형태 데이터 내보내기 (CSV / XLSX)
+// - CSV는 Excel에서 한글이 깨지지 않도록 UTF-8 BOM을 붙인다.
+// - XLSX는 외부 의존성 없이 시트 하나짜리 최소 구성(inline string 셀)으로 만든다.
+// - 학번/전화번호의 앞자리 0이 숫자로 바뀌지 않도록 모든 셀을 문자열로 쓴다.
+// - 사용자 입력(이름, User-Agent 등)이 수식으로 실행되지 않도록 모든 셀을 escapeCell로 거친다.
+
+// Format 내보내기 형식
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ErrUnknownFormat 지원하지 않는 형식
+var ErrUnknownFormat = errors.New("unknown format: must be csv or xlsx")
+
+// ParseFormat: 문자열 → Format (빈 문자열이면 csv)
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return f, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// ContentType: 응답 Content-Type
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// WriteTable: header + rows를 format으로 기록 (sheet는 XLSX 시트 이름)
+func WriteTable(w io.Writer, format Format, sheet string, header []string, rows [][]string) error {
+	header = escapeRow(header)
+	escaped := make([][]string, len(rows))
+	for i, row := range rows {
+		escaped[i] = escapeRow(row)
+	}
+	rows = escaped
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, header, rows)
+	case FormatXLSX:
+		return writeXLSX(w, sheet, header, rows)
+	}
+	return ErrUnknownFormat
+}
+
+// escapeCell: 수식 주입(CSV/formula injection) 방지
+// - =, +, -, @, 탭, CR로 시작하는 셀은 스프레드시트가 수식으로 해석하므로 앞에 '를 붙여 문자열로 만든다.
+func escapeCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// escapeRow: 호출자 슬라이스는 건드리지 않고 escape한 복사본 반환
+func escapeRow(row []string) []string {
+	out := make([]string, len(row))
+	for i, v := range row {
+		out[i] = escapeCell(v)
+	}
+	return out
+}
+
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// XLSX 고정 구성 파일
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+)
+
+func writeXLSX(w io.Writer, sheet string, header []string, rows [][]string) error {
+	zw := zip.NewWriter(w)
+
+	var workbook bytes.Buffer
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="`)
+	xmlEscape(&workbook, sheetName(sheet))
+	workbook.WriteString(`" sheetId="1" r:id="rId1"/></sheets></workbook>`)
+
+	var ws bytes.Buffer
+	ws.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	writeXLSXRow(&ws, 1, header)
+	for i, row := range rows {
+		writeXLSXRow(&ws, i+2, row)
+	}
+	ws.WriteString(`</sheetData></worksheet>`)
+
+	files := []struct {
+		name string
+		body []byte
+	}{
+		{"[Content_Types].xml", []byte(xlsxContentTypes)},
+		{"_rels/.rels", []byte(xlsxRootRels)},
+		{"xl/workbook.xml", workbook.Bytes()},
+		{"xl/_rels/workbook.xml.rels", []byte(xlsxWorkbookRels)},
+		{"xl/worksheets/sheet1.xml", ws.Bytes()},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeXLSXRow(b *bytes.Buffer, n int, cells []string) {
+	b.WriteString(`<row r="` + strconv.Itoa(n) + `">`)
+	for i, v := range cells {
+		b.WriteString(`<c r="` + columnName(i) + strconv.Itoa(n) + `" t="inlineStr"><is><t xml:space="preserve">`)
+		xmlEscape(b, v)
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+}
+
+// columnName: 0 → A, 25 → Z, 26 → AA
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// sheetName: Excel 시트 이름 제약(31자, []:*?/\ 금지)에 맞춤
+func sheetName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, s)
+	if r := []rune(s); len(r) > 31 {
+		s = string(r[:31])
+	}
+	if s == "" {
+		s = "Sheet1"
+	}
+	return s
+}
+
+func xmlEscape(b *bytes.Buffer, s string) {
+	_ = xml.EscapeText(b, []byte(s))
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Monitor: Redis 상태를 주기적으로 ping 해서 기억해 두는 감시기
+// - 요청마다 Redis에 붙어 보다가 타임아웃까지 기다리는 대신, Up()으로 즉시 판단한다.
+// - Redis가 내려가면 hold 같은 쓰기 경로는 바로 503, 조회 API는 그대로 동작 (degraded mode)
+type Monitor struct {
+	rdb      *redis.Client
+	interval time.Duration
+
+	mu        sync.RWMutex
+	up        bool
+	latency   time.Duration
+	lastErr   error
+	checkedAt time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewMonitor: Redis 감시기 생성 (2초마다 ping)
+func NewMonitor(rdb *redis.Client) *Monitor {
+	return &Monitor{rdb: rdb, interval: 2 * time.Second}
+}
+
+// Start: 첫 ping을 바로 수행하고 주기 감시 시작
+func (m *Monitor) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	m.check(ctx)
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.check(ctx)
+			}
+		}
+	}()
+}
+
+// Stop: 감시 중지
+func (m *Monitor) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	<-m.done
+}
+
+// Up: 마지막 ping 성공 여부
+func (m *Monitor) Up() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.up
+}
+
+// Last: 마지막 ping 결과 (지연 시간, 확인 시각, 에러)
+func (m *Monitor) Last() (latency time.Duration, checkedAt time.Time, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.latency, m.checkedAt, m.lastErr
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	began := time.Now()
+	err := m.rdb.Ping(pingCtx).Err()
+	latency := time.Since(began)
+	if ctx.Err() != nil {
+		return // 종료 중
+	}
+
+	m.mu.Lock()
+	was := m.up
+	m.up = err == nil
+	m.latency = latency
+	m.lastErr = err
+	m.checkedAt = time.Now()
+	m.mu.Unlock()
+
+	if was != (err == nil) {
+		if err != nil {
+			log.Printf("Redis is down, entering degraded (read-only) mode: %v", err)
+		} else {
+			log.Printf("Redis is up (%s)", latency.Round(time.Millisecond))
+		}
+	}
+}
@@ -0,0 +1,15 @@
+-- 세션(디바이스) 관리를 위한 auth_refresh_tokens 확장
+-- - access_jti: 해당 세션으로 마지막에 발급된 access token의 jti (세션 revoke 시 블랙리스트용)
+-- - last_used_at: 마지막으로 refresh에 사용된 시각
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+ALTER TABLE auth_refresh_tokens ADD COLUMN IF NOT EXISTS access_jti TEXT;
+ALTER TABLE auth_refresh_tokens ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP WITHOUT TIME ZONE;
+
+-- 사용자별 활성 세션 조회용
+CREATE INDEX IF NOT EXISTS idx_auth_refresh_tokens_active
+    ON auth_refresh_tokens (user_serial_id, issued_at DESC)
+    WHERE revoked_at IS NULL;
+
+COMMIT;
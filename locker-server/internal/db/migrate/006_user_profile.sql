@@ -0,0 +1,9 @@
+-- 프로필 수정 / 계정 삭제(익명화) 지원
+-- - deleted_at: 탈퇴 처리 시각. 행은 지우지 않고 개인정보만 익명화해서
+--   locker_assignments 등 히스토리(FK ON DELETE CASCADE)가 사라지지 않게 한다.
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITHOUT TIME ZONE;
+
+COMMIT;
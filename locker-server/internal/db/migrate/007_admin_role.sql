@@ -0,0 +1,12 @@
+-- 관리자(학생회) 계정 구분
+-- - is_admin=true 인 사용자만 /api/v1/admin/* 엔드포인트 접근 가능
+-- - 관리자 지정은 DB에서 직접: UPDATE users SET is_admin = true WHERE serial_id = ...;
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT false;
+
+-- 중복 계정 리포트(학번 기준 그룹핑)용
+CREATE INDEX IF NOT EXISTS idx_users_student_id ON users (student_id);
+
+COMMIT;
@@ -0,0 +1,31 @@
+-- serial_id 발급 방식 변경 (internal/serial)
+-- - 기존: SHA256(학번+이름+전화번호+"ku_info") → 누구나 계산 가능, 충돌 감지 없음
+-- - 신규: HMAC-SHA256(USER_SERIAL_SECRET, ...) + 충돌 시 재시도
+--
+-- 기존 계정의 serial_id는 DB에 저장된 값이라 그대로 유효하다 (로그인은 (학번, 이름, 전화번호)로 조회).
+-- 기존 값을 신규 방식으로 바꾸고 싶으면 관리자 API로 재발급한다:
+--   POST /api/v1/admin/users/rekey-serials {"dry_run": true}
+-- 재발급 시 serial_id를 참조하는 행이 함께 바뀌도록 FK에 ON UPDATE CASCADE를 추가한다.
+BEGIN;
+
+-- 1 = legacy sha256, 2 = hmac
+ALTER TABLE users ADD COLUMN IF NOT EXISTS serial_scheme SMALLINT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_users_serial_scheme ON users (serial_scheme) WHERE serial_scheme = 1;
+
+ALTER TABLE auth_refresh_tokens DROP CONSTRAINT IF EXISTS fk_refresh_user_serial;
+ALTER TABLE auth_refresh_tokens
+    ADD CONSTRAINT fk_refresh_user_serial FOREIGN KEY (user_serial_id)
+    REFERENCES users(serial_id) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE locker_info DROP CONSTRAINT IF EXISTS fk_locker_owner_serial;
+ALTER TABLE locker_info
+    ADD CONSTRAINT fk_locker_owner_serial FOREIGN KEY (owner_serial_id)
+    REFERENCES users(serial_id) ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE locker_assignments DROP CONSTRAINT IF EXISTS fk_assignment_user_serial;
+ALTER TABLE locker_assignments
+    ADD CONSTRAINT fk_assignment_user_serial FOREIGN KEY (user_serial_id)
+    REFERENCES users(serial_id) ON DELETE CASCADE ON UPDATE CASCADE;
+
+COMMIT;
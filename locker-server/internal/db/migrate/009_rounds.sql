@@ -0,0 +1,26 @@
+-- 신청 회차(round) 설정
+-- - 신청 기간과 hold 정책(TTL, 연장 횟수)을 회차별로 DB에서 관리한다.
+-- - is_active=true 인 회차가 없으면 환경변수(LOCKER_APPLICATION_START/END, HOLD_TTL_SEC, HOLD_MAX_EXTENSIONS)로 동작한다.
+-- - 활성 회차 지정 예:
+--   UPDATE rounds SET is_active = (round_id = 3);
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS rounds (
+    round_id            SERIAL PRIMARY KEY,
+    name                VARCHAR(100) NOT NULL,
+    opens_at            TIMESTAMPTZ,                    -- NULL이면 시작 제한 없음
+    closes_at           TIMESTAMPTZ,                    -- NULL이면 마감 제한 없음
+    hold_ttl_sec        INT NOT NULL DEFAULT 60 CHECK (hold_ttl_sec > 0),
+    max_hold_extensions INT NOT NULL DEFAULT 1 CHECK (max_hold_extensions >= 0),
+    is_active           BOOLEAN NOT NULL DEFAULT false,
+    created_at          TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- 활성 회차는 최대 1개
+CREATE UNIQUE INDEX IF NOT EXISTS ux_rounds_active ON rounds (is_active) WHERE is_active;
+
+-- hold 연장 횟수 (POST /lockers/:id/hold/extend)
+ALTER TABLE locker_assignments ADD COLUMN IF NOT EXISTS extend_count INT NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- 회차별 바로 확정(direct confirm) 모드
+-- - direct_confirm=true 인 회차에서는 POST /api/v1/lockers/:id/claim 한 번으로 hold 없이 바로 확정된다.
+-- - 소규모 회차처럼 선점 경쟁이 적을 때 hold → confirm 두 단계를 생략하기 위한 설정.
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+ALTER TABLE rounds ADD COLUMN IF NOT EXISTS direct_confirm BOOLEAN NOT NULL DEFAULT false;
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- hold 만료 처리를 Postgres hold_expires_at 기준으로 수행 (scheduler.ExpireDueHolds)
+-- - Redis keyspace 알림은 유실될 수 있고 CONFIG SET 권한이 필요해서, 만료의 기준을 DB로 옮긴다.
+-- - 주기 작업이 state='hold' AND hold_expires_at <= now() 인 행을 FOR UPDATE SKIP LOCKED로 가져가 expired로 바꾼다.
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_assignments_hold_expiry
+    ON locker_assignments (hold_expires_at)
+    WHERE state = 'hold';
+
+COMMIT;
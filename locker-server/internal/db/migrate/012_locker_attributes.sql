@@ -0,0 +1,19 @@
+-- 사물함 속성 (크기, 층(위/중간/아래 칸), 콘센트 근처 여부, 고장/사용 중지)
+-- - 행이 없으면 속성 미입력 + 사용 가능으로 본다.
+-- - out_of_service=true 인 사물함은 hold/claim이 거절되고 목록에서 status=out_of_service로 보인다.
+-- - 관리자 수정: PATCH /api/v1/admin/lockers/:id/attributes
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS locker_attributes (
+    locker_id      INTEGER PRIMARY KEY REFERENCES locker_info(locker_id) ON DELETE CASCADE,
+    size           VARCHAR(10) CHECK (size IN ('small', 'medium', 'large')),     -- NULL이면 미입력
+    row_position   VARCHAR(10) CHECK (row_position IN ('top', 'middle', 'bottom')), -- NULL이면 미입력
+    near_outlet    BOOLEAN NOT NULL DEFAULT false,
+    out_of_service BOOLEAN NOT NULL DEFAULT false,
+    service_note   TEXT,                                                          -- 고장 내용 등 관리자 메모
+    updated_at     TIMESTAMP NOT NULL DEFAULT now(),
+    updated_by     BIGINT                                                         -- 마지막으로 수정한 관리자 serial_id
+);
+
+COMMIT;
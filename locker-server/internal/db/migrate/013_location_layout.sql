@@ -0,0 +1,19 @@
+-- 위치별 사물함 배치도 (격자)
+-- - locker_locations.grid_rows/grid_cols: 격자 크기 (NULL이면 배치도 없음)
+-- - locker_info.grid_row/grid_col: 격자 안 위치 (1부터, row 1이 맨 위 칸). NULL이면 배치되지 않음
+-- - 같은 위치 안에서 한 칸에는 사물함 하나만 둘 수 있다.
+-- - 관리자 수정: PUT /api/v1/admin/locations/:id/layout (위치 단위로 통째로 교체)
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+ALTER TABLE locker_locations ADD COLUMN IF NOT EXISTS grid_rows INT CHECK (grid_rows > 0);
+ALTER TABLE locker_locations ADD COLUMN IF NOT EXISTS grid_cols INT CHECK (grid_cols > 0);
+
+ALTER TABLE locker_info ADD COLUMN IF NOT EXISTS grid_row INT CHECK (grid_row > 0);
+ALTER TABLE locker_info ADD COLUMN IF NOT EXISTS grid_col INT CHECK (grid_col > 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_locker_info_grid_cell
+    ON locker_info (location_id, grid_row, grid_col)
+    WHERE grid_row IS NOT NULL AND grid_col IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- 신청 자격 명단 (학생회 명단 CSV로 가져옴)
+-- - 관리자 가져오기: POST /api/v1/admin/import/roster (CSV, dry_run/replace 지원) 또는 `server import roster <file>`
+-- - name이 있으면 가입 정보의 이름과 비교하는 데 쓴다.
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS eligible_students (
+    student_id  VARCHAR(20) PRIMARY KEY,
+    name        VARCHAR(100),
+    department  VARCHAR(100),
+    note        TEXT,
+    imported_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+COMMIT;
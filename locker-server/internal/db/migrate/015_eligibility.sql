@@ -0,0 +1,34 @@
+-- 회차별 신청 자격 규칙 + 학생별 예외
+-- - 규칙은 모두 선택 사항이며, 설정된 규칙은 전부 만족해야 한다 (AND). 아무 규칙도 없으면 누구나 신청 가능.
+--   eligibility_roster   : eligible_students 명단에 있어야 함
+--   eligibility_fee_paid : 명단의 fee_paid(학생회비 납부)가 true여야 함
+--   eligible_id_prefixes : 학번이 이 중 하나로 시작해야 함 (예: {'2023320','2024320'})
+--   eligible_year_min/max: 학번 앞 4자리(입학년도) 범위
+-- - 로그인과 POST /lockers/:id/hold, /claim 에서 검사하며, 실패하면 403과 사유를 돌려준다.
+-- - eligibility_exceptions: 관리자가 학생별로 허용 (round_id NULL이면 모든 회차)
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+ALTER TABLE rounds ADD COLUMN IF NOT EXISTS eligibility_roster   BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE rounds ADD COLUMN IF NOT EXISTS eligibility_fee_paid BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE rounds ADD COLUMN IF NOT EXISTS eligible_id_prefixes TEXT[];
+ALTER TABLE rounds ADD COLUMN IF NOT EXISTS eligible_year_min    INT;
+ALTER TABLE rounds ADD COLUMN IF NOT EXISTS eligible_year_max    INT;
+
+-- 명단 가져오기(roster CSV)의 fee_paid 열
+ALTER TABLE eligible_students ADD COLUMN IF NOT EXISTS fee_paid BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS eligibility_exceptions (
+    exception_id SERIAL PRIMARY KEY,
+    student_id   VARCHAR(20) NOT NULL,
+    round_id     INT REFERENCES rounds(round_id) ON DELETE CASCADE, -- NULL이면 모든 회차
+    reason       TEXT NOT NULL,
+    granted_by   BIGINT,                                            -- 허용한 관리자 serial_id
+    created_at   TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- 학생당 회차별(또는 전체) 예외 1건
+CREATE UNIQUE INDEX IF NOT EXISTS ux_eligibility_exceptions_student_round
+    ON eligibility_exceptions (student_id, COALESCE(round_id, 0));
+
+COMMIT;
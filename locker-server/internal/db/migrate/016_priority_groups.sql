@@ -0,0 +1,30 @@
+-- 회차별 우선 순위 그룹 (그룹마다 신청 시작 시각을 앞당김)
+-- - 예: 4학년(year_max=2022)은 09:00, 학생회비 납부자(fee_paid)는 09:30, 나머지는 rounds.opens_at(10:00)
+-- - 학생은 조건(015_eligibility.sql의 회차 규칙과 같은 의미)을 만족하는 그룹 중 가장 이른 opens_at을 쓴다.
+--   조건이 하나도 없는 그룹은 모든 학생에게 해당한다.
+-- - rounds.opens_at보다 늦은 그룹은 의미가 없고(일반 시작 시각이 먼저), 마감은 회차 closes_at 하나다.
+-- - 관리자 예외(eligibility_exceptions)는 자격만 허용하며 우선 순위를 주지는 않는다.
+-- - 그룹 추가 예:
+--   INSERT INTO round_priority_groups (round_id, name, opens_at, year_max)
+--   VALUES (3, '4학년', '2025-03-02 09:00+09', 2022);
+-- - 학생 본인 일정: GET /api/v1/rounds/current/me
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS round_priority_groups (
+    group_id    SERIAL PRIMARY KEY,
+    round_id    INT NOT NULL REFERENCES rounds(round_id) ON DELETE CASCADE,
+    name        VARCHAR(100) NOT NULL,
+    opens_at    TIMESTAMPTZ NOT NULL,
+    roster      BOOLEAN NOT NULL DEFAULT false, -- eligible_students 명단에 있어야 함
+    fee_paid    BOOLEAN NOT NULL DEFAULT false, -- 명단의 fee_paid가 true여야 함
+    id_prefixes TEXT[],                         -- 학번 접두사 중 하나
+    year_min    INT,                            -- 입학년도(학번 앞 4자리) 하한
+    year_max    INT,                            -- 입학년도 상한
+    created_at  TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (round_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS ix_round_priority_groups_round ON round_priority_groups (round_id, opens_at);
+
+COMMIT;
@@ -0,0 +1,41 @@
+-- 감사 로그 (append-only)
+-- - 사물함 상태 변경, 로그인/로그아웃/refresh, 세션 revoke, 프로필 변경/탈퇴, 관리자 작업을 한 줄씩 기록한다.
+-- - 각 변경과 같은 트랜잭션에서 INSERT 하므로, 변경이 커밋되면 기록도 반드시 남는다 (internal/audit).
+-- - UPDATE/DELETE는 트리거로 막는다. 보관 기간이 지나 정리가 필요하면 트리거를 잠시 끄고 관리자가 직접 지운다.
+-- - actor_serial_id는 FK가 아니다 (계정 병합/탈퇴/serial 재발급 이후에도 당시 값을 그대로 보존).
+-- - 조회/CSV 내보내기: GET /api/v1/admin/audit, GET /api/v1/admin/audit/export
+-- - 같은 migration에서 hold 해제(release-hold)가 행을 지우지 않고 state='cancelled'로 남기도록 바뀌었다.
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    event_id        BIGSERIAL PRIMARY KEY,
+    occurred_at     TIMESTAMP NOT NULL DEFAULT clock_timestamp(),
+    actor_serial_id BIGINT,                -- NULL이면 시스템(스케줄러 등) 또는 익명
+    actor_role      VARCHAR(20) NOT NULL CHECK (actor_role IN ('user', 'admin', 'system')),
+    action          VARCHAR(50) NOT NULL,  -- 예: locker.hold, auth.login, admin.user_merge
+    target_type     VARCHAR(30) NOT NULL,  -- locker | user | session | location | import | eligibility_exception
+    target_id       VARCHAR(64),
+    before_state    JSONB,
+    after_state     JSONB,
+    ip              VARCHAR(64),
+    user_agent      TEXT
+);
+
+CREATE INDEX IF NOT EXISTS ix_audit_events_target ON audit_events (target_type, target_id, event_id);
+CREATE INDEX IF NOT EXISTS ix_audit_events_actor ON audit_events (actor_serial_id, event_id);
+CREATE INDEX IF NOT EXISTS ix_audit_events_action ON audit_events (action, event_id);
+CREATE INDEX IF NOT EXISTS ix_audit_events_occurred ON audit_events (occurred_at);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_events_append_only ON audit_events;
+CREATE TRIGGER trg_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- 사물함 배정 히스토리 조회 (GET /lockers/me/history, GET /admin/lockers/:id/history)
+-- - 최신순 keyset 페이지네이션(assignment_id < cursor ORDER BY assignment_id DESC)을 인덱스만으로 처리한다.
+-- - 사물함별 조회는 idx_assignments_lookup(locker_id, state)로도 거를 수 있지만 정렬이 안 되므로 따로 둔다.
+-- Safe to run multiple times due to IF NOT EXISTS
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_assignments_user_history
+    ON locker_assignments (user_serial_id, assignment_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_assignments_locker_history
+    ON locker_assignments (locker_id, assignment_id DESC);
+
+COMMIT;
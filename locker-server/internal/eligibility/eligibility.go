@@ -0,0 +1,177 @@
+package eligibility
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// 회차 신청 자격 검사
+// - 규칙: 명단(eligible_students), 학번 접두사, 입학년도(학번 앞 4자리) 범위, 학생회비 납부
+// - 설정된 규칙은 모두 만족해야 한다 (AND). 아무 규칙도 없으면 누구나 통과.
+// - 관리자가 준 예외(eligibility_exceptions)가 있으면 규칙과 상관없이 통과.
+// - 규칙은 rounds 테이블 컬럼(015_eligibility.sql)에서 읽고, 활성 회차가 없으면 환경변수를 쓴다.
+//   ELIGIBILITY_ROSTER, ELIGIBILITY_FEE_PAID (bool)
+//   ELIGIBLE_ID_PREFIXES (쉼표 구분), ELIGIBLE_YEAR_MIN / ELIGIBLE_YEAR_MAX
+// - 같은 규칙(Rules.Match)으로 우선 순위 그룹 소속도 판정한다 (internal/round).
+
+// Rules 회차 신청 자격 규칙
+type Rules struct {
+	Roster   bool     // eligible_students 명단에 있어야 함
+	FeePaid  bool     // 명단의 fee_paid가 true여야 함
+	Prefixes []string // 학번이 이 중 하나로 시작해야 함 (비어 있으면 제한 없음)
+	YearMin  int      // 입학년도 하한 (0이면 제한 없음)
+	YearMax  int      // 입학년도 상한 (0이면 제한 없음)
+}
+
+// Empty: 설정된 규칙이 없는지
+func (r Rules) Empty() bool {
+	return !r.Roster && !r.FeePaid && len(r.Prefixes) == 0 && r.YearMin == 0 && r.YearMax == 0
+}
+
+// FromEnv: 환경변수 기반 규칙 (활성 회차가 없을 때)
+func FromEnv() Rules {
+	r := Rules{
+		Roster:  util.EnvBool("ELIGIBILITY_ROSTER", false),
+		FeePaid: util.EnvBool("ELIGIBILITY_FEE_PAID", false),
+		YearMin: util.EnvInt("ELIGIBLE_YEAR_MIN", 0),
+		YearMax: util.EnvInt("ELIGIBLE_YEAR_MAX", 0),
+	}
+	for _, p := range strings.Split(os.Getenv("ELIGIBLE_ID_PREFIXES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			r.Prefixes = append(r.Prefixes, p)
+		}
+	}
+	return r
+}
+
+// 불합격 사유 코드
+const (
+	ReasonNotOnRoster   = "not_on_roster"
+	ReasonNameMismatch  = "roster_name_mismatch"
+	ReasonFeeUnpaid     = "fee_unpaid"
+	ReasonIDPrefix      = "student_id_prefix"
+	ReasonAdmissionYear = "admission_year"
+)
+
+// Decision 검사 결과
+type Decision struct {
+	Eligible  bool   `json:"eligible" example:"false"`
+	Reason    string `json:"reason,omitempty" example:"not_on_roster"`                                            // 불합격 사유 코드
+	Message   string `json:"message,omitempty" example:"student ID is not on the eligible roster for this round"` // 사용자에게 보여줄 설명
+	Exception bool   `json:"exception,omitempty" example:"false"`                                                 // 관리자 예외로 통과
+}
+
+func deny(reason, format string, args ...any) Decision {
+	return Decision{Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+// Student 검사에 필요한 학생 정보 (명단 + 예외)
+type Student struct {
+	ID         string
+	OnRoster   bool   // eligible_students에 있음
+	RosterName string // 명단의 이름 (없으면 빈 문자열)
+	FeePaid    bool   // 명단의 fee_paid
+	Exception  bool   // 회차(또는 전체)에 대한 관리자 예외가 있음
+}
+
+// Lookup: 회차(roundID, 환경변수 회차는 0)에 대한 학생 정보 조회 (예외 + 명단을 한 번에)
+func Lookup(ctx context.Context, db *pgxpool.Pool, roundID int64, studentID string) (Student, error) {
+	var (
+		st         = Student{ID: studentID}
+		rosterName *string
+		feePaid    *bool
+	)
+	err := db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM eligibility_exceptions
+		                 WHERE student_id = $1 AND (round_id IS NULL OR round_id = $2)),
+		        es.name, es.fee_paid
+		   FROM (SELECT 1) one
+		   LEFT JOIN eligible_students es ON es.student_id = $1`,
+		studentID, roundID).Scan(&st.Exception, &rosterName, &feePaid)
+	if err != nil {
+		return Student{}, err
+	}
+	// 명단에 없으면 rosterName/feePaid가 NULL
+	if feePaid != nil {
+		st.OnRoster, st.FeePaid = true, *feePaid
+	}
+	if rosterName != nil {
+		st.RosterName = *rosterName
+	}
+	return st, nil
+}
+
+// Check: studentID가 회차의 자격을 만족하는지 (규칙이 없으면 DB 조회 없이 통과)
+// - name이 비어 있지 않고 명단에 이름이 있으면 이름도 비교한다 (가입/로그인 시).
+func Check(ctx context.Context, db *pgxpool.Pool, roundID int64, rules Rules, studentID, name string) (Decision, error) {
+	if rules.Empty() {
+		return Decision{Eligible: true}, nil
+	}
+	st, err := Lookup(ctx, db, roundID, studentID)
+	if err != nil {
+		return Decision{}, err
+	}
+	return Decide(rules, st, name), nil
+}
+
+// Decide: 조회한 학생 정보로 자격 판정 (관리자 예외가 있으면 규칙과 상관없이 통과)
+func Decide(rules Rules, st Student, name string) Decision {
+	if rules.Empty() {
+		return Decision{Eligible: true}
+	}
+	if st.Exception {
+		return Decision{Eligible: true, Exception: true}
+	}
+	return rules.Match(st, name)
+}
+
+// Match: 규칙만으로 판정 (예외 무시 - 우선 순위 그룹 판정 등에 사용)
+func (r Rules) Match(st Student, name string) Decision {
+	if len(r.Prefixes) > 0 && !hasAnyPrefix(st.ID, r.Prefixes) {
+		return deny(ReasonIDPrefix, "student ID must start with one of %s", strings.Join(r.Prefixes, ", "))
+	}
+	if r.YearMin > 0 || r.YearMax > 0 {
+		year, _ := strconv.Atoi(st.ID[:min(4, len(st.ID))])
+		if (r.YearMin > 0 && year < r.YearMin) || (r.YearMax > 0 && year > r.YearMax) {
+			return deny(ReasonAdmissionYear, "admission year %d is outside the eligible range %s", year, yearRange(r))
+		}
+	}
+	if r.Roster || r.FeePaid {
+		if !st.OnRoster {
+			return deny(ReasonNotOnRoster, "student ID is not on the eligible roster for this round")
+		}
+		if name != "" && st.RosterName != "" && st.RosterName != name {
+			return deny(ReasonNameMismatch, "name does not match the eligible roster for this student ID")
+		}
+		if r.FeePaid && !st.FeePaid {
+			return deny(ReasonFeeUnpaid, "membership fee has not been paid")
+		}
+	}
+	return Decision{Eligible: true}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// yearRange: "2020-2024", "2020 or later", "2024 or earlier"
+func yearRange(r Rules) string {
+	switch {
+	case r.YearMin > 0 && r.YearMax > 0:
+		return fmt.Sprintf("%d-%d", r.YearMin, r.YearMax)
+	case r.YearMin > 0:
+		return fmt.Sprintf("%d or later", r.YearMin)
+	}
+	return fmt.Sprintf("%d or earlier", r.YearMax)
+}
@@ -0,0 +1,125 @@
+package floormap
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+)
+
+// 위치별 사물함 배치도를 SVG로 그린다 (GET /locations/:id/map.svg)
+// - 격자 한 칸 = 사물함 하나, 칸 안에 사물함 번호를 쓰고 상태별 색으로 채운다.
+// - 상태 값은 lockercache.Status*. 모르는 상태는 회색.
+// - 배치되지 않은 칸은 점선 테두리만 그린다.
+
+// 칸 크기/여백 (px)
+const (
+	cellSize = 48
+	gap      = 6
+	margin   = 16
+	titleH   = 28
+	legendH  = 28
+)
+
+// statusColors 상태별 채움 색 (순서 = 범례 순서)
+var statusColors = []struct {
+	status, label, color string
+}{
+	{lockercache.StatusFree, "사용 가능", "#4caf50"},
+	{lockercache.StatusHeld, "선점 중", "#ffb300"},
+	{lockercache.StatusTaken, "사용 중", "#9e9e9e"},
+	{lockercache.StatusOutOfService, "사용 중지", "#e53935"},
+}
+
+// Cell 배치된 사물함 한 칸 (Row/Col은 1부터)
+type Cell struct {
+	LockerID int
+	Row      int
+	Col      int
+	Status   string
+}
+
+// Map 한 위치의 배치도
+type Map struct {
+	Name  string
+	Rows  int
+	Cols  int
+	Cells []Cell
+}
+
+// RenderSVG: 배치도를 SVG 문서로 렌더링 (격자 밖 칸은 무시)
+func RenderSVG(m Map) []byte {
+	width := 2*margin + m.Cols*cellSize + max(m.Cols-1, 0)*gap
+	width = max(width, 2*margin+len(statusColors)*110)
+	height := 2*margin + titleH + m.Rows*cellSize + max(m.Rows-1, 0)*gap + legendH
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`,
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`, width, height)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="16" font-weight="bold">%s</text>`,
+		margin, margin+16, html.EscapeString(m.Name))
+
+	originY := margin + titleH
+	placed := map[[2]int]bool{}
+	for _, cell := range m.Cells {
+		if cell.Row < 1 || cell.Row > m.Rows || cell.Col < 1 || cell.Col > m.Cols {
+			continue
+		}
+		placed[[2]int{cell.Row, cell.Col}] = true
+		x, y := cellOrigin(cell.Row, cell.Col, originY)
+		fmt.Fprintf(&b, `<g data-locker-id="%d" data-status="%s">`, cell.LockerID, html.EscapeString(cell.Status))
+		fmt.Fprintf(&b, `<title>%d (%s)</title>`, cell.LockerID, html.EscapeString(labelOf(cell.Status)))
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="%s" stroke="#424242"/>`,
+			x, y, cellSize, cellSize, colorOf(cell.Status))
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="13" text-anchor="middle" fill="#ffffff">%d</text>`,
+			x+cellSize/2, y+cellSize/2+5, cell.LockerID)
+		b.WriteString(`</g>`)
+	}
+
+	// 빈 칸
+	for r := 1; r <= m.Rows; r++ {
+		for c := 1; c <= m.Cols; c++ {
+			if placed[[2]int{r, c}] {
+				continue
+			}
+			x, y := cellOrigin(r, c, originY)
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="none" stroke="#bdbdbd" stroke-dasharray="4 3"/>`,
+				x, y, cellSize, cellSize)
+		}
+	}
+
+	// 범례
+	legendY := height - margin - 12
+	for i, s := range statusColors {
+		x := margin + i*110
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`, x, legendY, s.color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="12">%s</text>`, x+18, legendY+11, s.label)
+	}
+
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+func cellOrigin(row, col, originY int) (x, y int) {
+	return margin + (col-1)*(cellSize+gap), originY + (row-1)*(cellSize+gap)
+}
+
+func colorOf(status string) string {
+	for _, s := range statusColors {
+		if s.status == status {
+			return s.color
+		}
+	}
+	return "#757575"
+}
+
+func labelOf(status string) string {
+	for _, s := range statusColors {
+		if s.status == status {
+			return s.label
+		}
+	}
+	return status
+}
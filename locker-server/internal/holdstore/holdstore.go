@@ -0,0 +1,222 @@
+package holdstore
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 사물함 hold(선점) 상태를 Redis에 원자적으로 저장/해제하는 저장소
+// - locker:hold:{locker_id} = serial_id   (사물함당 1개의 hold)
+// - user:hold:{serial_id}   = locker_id   (사용자당 1개의 hold)
+// - 두 키는 항상 같은 TTL로 함께 만들어지고 함께 지워진다.
+// - 해제/연장은 저장된 serial_id가 요청자와 같을 때만 수행 (남의 새 hold를 지우는 문제 방지)
+// - 모든 연산은 Lua 스크립트로 한 번에 실행되므로 중간 상태가 다른 요청에 보이지 않는다.
+
+var (
+	// ErrLockerHeld 다른 사람이(또는 본인이) 이미 이 사물함을 hold 중
+	ErrLockerHeld = errors.New("locker already held")
+	// ErrUserHolding 요청자가 이미 다른 사물함을 hold 중
+	ErrUserHolding = errors.New("user already holds another locker")
+	// ErrNotOwner hold가 없거나 다른 사람 소유
+	ErrNotOwner = errors.New("hold not found or owned by someone else")
+)
+
+// LockerKey: 사물함 hold 키 ("locker:hold:{id}")
+func LockerKey(lockerID int) string {
+	return "locker:hold:" + strconv.Itoa(lockerID)
+}
+
+// UserKey: 사용자 hold 키 ("user:hold:{serial_id}")
+func UserKey(serialID int64) string {
+	return "user:hold:" + strconv.FormatInt(serialID, 10)
+}
+
+// KEYS[1]=locker key, KEYS[2]=user key
+// ARGV[1]=serial_id, ARGV[2]=locker_id, ARGV[3]=ttl(ms)
+// 반환: 0=성공, 1=사물함이 이미 hold됨, 2=사용자가 다른 사물함을 hold 중
+var acquireScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+  return 1
+end
+local cur = redis.call('GET', KEYS[2])
+if cur and cur ~= ARGV[2] then
+  return 2
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+return 0
+`)
+
+// KEYS[1]=locker key, KEYS[2]=user key
+// ARGV[1]=serial_id, ARGV[2]=locker_id
+// 반환: 1=해제함, 0=hold 없음/소유자 아님
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 0
+end
+redis.call('DEL', KEYS[1])
+if redis.call('GET', KEYS[2]) == ARGV[2] then
+  redis.call('DEL', KEYS[2])
+end
+return 1
+`)
+
+// KEYS[1]=locker key, KEYS[2]=user key
+// ARGV[1]=serial_id, ARGV[2]=locker_id, ARGV[3]=ttl(ms)
+// 반환: 1=연장함, 0=hold 없음/소유자 아님
+var extendScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 0
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// Store Redis 기반 hold 저장소
+type Store struct {
+	rdb *redis.Client
+}
+
+// New: Redis 클라이언트로 hold 저장소 생성
+func New(rdb *redis.Client) *Store {
+	return &Store{rdb: rdb}
+}
+
+// Acquire: 사물함 hold 획득 (사물함당 1개, 사용자당 1개)
+// - 실패 시 ErrLockerHeld / ErrUserHolding, Redis 장애면 그 에러를 그대로 반환
+func (s *Store) Acquire(ctx context.Context, lockerID int, serialID int64, ttl time.Duration) error {
+	res, err := acquireScript.Run(ctx, s.rdb,
+		[]string{LockerKey(lockerID), UserKey(serialID)},
+		strconv.FormatInt(serialID, 10), strconv.Itoa(lockerID), ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return err
+	}
+	switch res {
+	case 0:
+		return nil
+	case 1:
+		return ErrLockerHeld
+	default:
+		return ErrUserHolding
+	}
+}
+
+// Release: 요청자가 소유한 hold만 해제
+// - 소유자가 아니거나 이미 만료됐으면 ErrNotOwner
+func (s *Store) Release(ctx context.Context, lockerID int, serialID int64) error {
+	res, err := releaseScript.Run(ctx, s.rdb,
+		[]string{LockerKey(lockerID), UserKey(serialID)},
+		strconv.FormatInt(serialID, 10), strconv.Itoa(lockerID),
+	).Int()
+	if err != nil {
+		return err
+	}
+	if res == 0 {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// Extend: 요청자가 소유한 hold의 TTL을 ttl로 다시 설정
+// - 소유자가 아니거나 이미 만료됐으면 ErrNotOwner
+func (s *Store) Extend(ctx context.Context, lockerID int, serialID int64, ttl time.Duration) error {
+	res, err := extendScript.Run(ctx, s.rdb,
+		[]string{LockerKey(lockerID), UserKey(serialID)},
+		strconv.FormatInt(serialID, 10), strconv.Itoa(lockerID), ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return err
+	}
+	if res == 0 {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// Exists: 사물함 hold 키가 살아있는지 (스케줄러의 만료 판정용)
+func (s *Store) Exists(ctx context.Context, lockerID int) (bool, error) {
+	n, err := s.rdb.Exists(ctx, LockerKey(lockerID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// LockerKeyPattern / UserKeyPattern: SCAN 용 패턴 (reconcile에서 사용)
+const (
+	LockerKeyPattern = "locker:hold:*"
+	UserKeyPattern   = "user:hold:*"
+)
+
+// ParseLockerKey: "locker:hold:{id}" → id
+func ParseLockerKey(key string) (int, bool) {
+	s, ok := strings.CutPrefix(key, "locker:hold:")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(s)
+	return id, err == nil
+}
+
+// ParseUserKey: "user:hold:{serial_id}" → serial_id
+func ParseUserKey(key string) (int64, bool) {
+	s, ok := strings.CutPrefix(key, "user:hold:")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	return id, err == nil
+}
+
+// Holder: 사물함 hold 소유자(serial_id)와 남은 TTL 조회. hold가 없으면 ok=false
+func (s *Store) Holder(ctx context.Context, lockerID int) (serialID int64, ttl time.Duration, ok bool, err error) {
+	pipe := s.rdb.Pipeline()
+	get := pipe.Get(ctx, LockerKey(lockerID))
+	pttl := pipe.PTTL(ctx, LockerKey(lockerID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, false, err
+	}
+	serialID, err = get.Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, 0, false, nil
+	}
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return serialID, pttl.Val(), true, nil
+}
+
+// KEYS[1]=user key
+// ARGV[1]=serial_id
+// 반환: 1=삭제함, 0=가리키는 사물함 키가 아직 이 사용자 소유라 유지
+// * 사물함 키 이름을 스크립트 안에서 만들기 때문에 단일 Redis(비클러스터) 전제
+var dropUserKeyScript = redis.NewScript(`
+local lockerID = redis.call('GET', KEYS[1])
+if not lockerID then
+  return 0
+end
+if redis.call('GET', 'locker:hold:' .. lockerID) == ARGV[1] then
+  return 0
+end
+redis.call('DEL', KEYS[1])
+return 1
+`)
+
+// DropStaleUserKey: 사용자 키가 가리키는 사물함 hold가 더 이상 이 사용자 것이 아니면 사용자 키 삭제
+func (s *Store) DropStaleUserKey(ctx context.Context, serialID int64) (bool, error) {
+	res, err := dropUserKeyScript.Run(ctx, s.rdb,
+		[]string{UserKey(serialID)},
+		strconv.FormatInt(serialID, 10),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
@@ -0,0 +1,79 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+)
+
+// InFlight 종료 시 끝까지 처리해야 하는 요청(hold/confirm 등) 추적기
+// - Begin이 false를 돌려주면 이미 drain 중이므로 새 요청을 받지 않는다 (핸들러 → 503)
+// - Drain은 새 요청을 막고, 진행 중인 요청이 모두 끝나거나 ctx가 끝날 때까지 기다린다.
+type InFlight struct {
+	mu       sync.Mutex
+	count    int
+	draining bool
+	idle     chan struct{} // count가 0이 되면 닫힘 (drain 중에만 사용)
+}
+
+// NewInFlight: 추적기 생성
+func NewInFlight() *InFlight {
+	return &InFlight{}
+}
+
+// Begin: 요청 시작. drain 중이면 false
+func (f *InFlight) Begin() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.draining {
+		return false
+	}
+	f.count++
+	return true
+}
+
+// Done: 요청 종료 (Begin이 true였을 때만 호출)
+func (f *InFlight) Done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.count--
+	if f.count == 0 && f.idle != nil {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// Count: 현재 진행 중인 요청 수
+func (f *InFlight) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// Draining: drain 중인지 (헬스 체크 등에서 사용)
+func (f *InFlight) Draining() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.draining
+}
+
+// Drain: 새 요청을 막고 진행 중인 요청이 끝나길 기다림. ctx가 먼저 끝나면 남은 요청 수와 함께 ctx 에러 반환
+func (f *InFlight) Drain(ctx context.Context) (remaining int, err error) {
+	f.mu.Lock()
+	f.draining = true
+	if f.count == 0 {
+		f.mu.Unlock()
+		return 0, nil
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	idle := f.idle
+	f.mu.Unlock()
+
+	select {
+	case <-idle:
+		return 0, nil
+	case <-ctx.Done():
+		return f.Count(), ctx.Err()
+	}
+}
@@ -0,0 +1,128 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 서버 구성 요소(HTTP, 스케줄러, pub/sub 리스너, DB/Redis)의 시작/종료 순서를 관리
+// - 루트 컨텍스트를 하나 가지고, 구성 요소는 여기서 파생된 컨텍스트로 동작한다.
+// - Start는 등록 순서대로, Shutdown은 역순으로 호출한다.
+// - 예: postgres → redis → scheduler → listener → http 순으로 등록하면 종료 시 http(요청 drain) → listener → scheduler → redis → postgres 순으로 닫힌다.
+// - 종료 진행 상황은 "[shutdown i/n]" 로그로 남긴다.
+
+// Component 시작/종료 가능한 구성 요소
+// - Start/Stop은 nil이어도 된다 (예: 이미 연결된 DB 풀은 Stop만 있음)
+type Component struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Manager 구성 요소 생명주기 관리자
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	components []Component
+	started    int
+
+	fatal chan error
+}
+
+// New: 루트 컨텍스트를 가진 관리자 생성
+func New() *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{ctx: ctx, cancel: cancel, fatal: make(chan error, 1)}
+}
+
+// Context: 루트 컨텍스트 (Shutdown이 끝나면 취소됨)
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Add: 구성 요소 등록 (등록 순서 = 시작 순서, 역순 = 종료 순서)
+func (m *Manager) Add(c Component) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, c)
+}
+
+// Start: 등록 순서대로 시작. 하나라도 실패하면 이미 시작한 것들을 역순으로 닫고 에러 반환
+func (m *Manager) Start() error {
+	m.mu.Lock()
+	components := append([]Component(nil), m.components...)
+	m.mu.Unlock()
+
+	for i, c := range components {
+		if c.Start != nil {
+			if err := c.Start(m.ctx); err != nil {
+				log.Printf("[startup] %s failed: %v", c.Name, err)
+				m.Shutdown(10 * time.Second)
+				return fmt.Errorf("start %s: %w", c.Name, err)
+			}
+		}
+		m.mu.Lock()
+		m.started = i + 1
+		m.mu.Unlock()
+		log.Printf("[startup %d/%d] %s started", i+1, len(components), c.Name)
+	}
+	return nil
+}
+
+// Fail: 실행 중 복구 불가능한 에러 보고 (예: HTTP Listen 실패) → Wait가 깨어남
+func (m *Manager) Fail(err error) {
+	select {
+	case m.fatal <- err:
+	default:
+	}
+}
+
+// Wait: 종료 신호(SIGINT/SIGTERM) 또는 Fail 보고까지 대기
+func (m *Manager) Wait() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	select {
+	case s := <-sig:
+		log.Printf("Received %s, shutting down...", s)
+	case err := <-m.fatal:
+		log.Printf("Fatal error, shutting down: %v", err)
+	}
+}
+
+// Shutdown: 시작된 구성 요소를 역순으로 종료 (전체 제한 시간 timeout), 마지막에 루트 컨텍스트 취소
+func (m *Manager) Shutdown(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	m.mu.Lock()
+	components := append([]Component(nil), m.components[:m.started]...)
+	m.started = 0
+	m.mu.Unlock()
+
+	total := len(components)
+	for i := total - 1; i >= 0; i-- {
+		c := components[i]
+		step := total - i
+		if c.Stop == nil {
+			continue
+		}
+		log.Printf("[shutdown %d/%d] stopping %s...", step, total, c.Name)
+		began := time.Now()
+		if err := c.Stop(ctx); err != nil {
+			log.Printf("[shutdown %d/%d] %s stopped with error after %s: %v", step, total, c.Name, time.Since(began).Round(time.Millisecond), err)
+			continue
+		}
+		log.Printf("[shutdown %d/%d] %s stopped (%s)", step, total, c.Name, time.Since(began).Round(time.Millisecond))
+	}
+	m.cancel()
+}
@@ -0,0 +1,295 @@
+package lockercache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// 사물함 목록(ListLockers) 스냅샷 캐시
+// - locker_info + locker_locations + 유효한 hold + locker_attributes(+ 배치도 위치) 조인 결과를 메모리에 스냅샷으로 들고, ETag를 미리 계산해 둔다.
+// - hold/confirm/release/expire 등 상태가 바뀌면 Publish → 이 인스턴스는 즉시 무효화하고,
+//   Redis 채널(locker:events)로 다른 인스턴스에도 알려 각자 무효화한다.
+// - 무효화된 스냅샷은 다음 요청에서 한 번만 다시 만든다 (동시에 들어온 요청은 같은 결과를 기다림).
+// - 이벤트가 유실돼도 LOCKER_CACHE_MAX_AGE_SEC(기본 30초)가 지나면 다시 만든다.
+// - 스냅샷에 들어 있는 hold 중 가장 먼저 끝나는 시각이 지나도 다시 만든다 (held → free가 ETag에 반영되도록).
+
+// Channel 사물함 상태 변경 이벤트 채널
+const Channel = "locker:events"
+
+// 이벤트 종류
+const (
+	EventHold    = "hold"
+	EventConfirm = "confirm"
+	EventRelease = "release"
+	EventExpire  = "expire"
+	EventAdmin   = "admin" // 관리자 작업 등 여러 사물함이 바뀔 수 있는 경우 (LockerID=0)
+)
+
+// Event 사물함 상태 변경 이벤트
+type Event struct {
+	LockerID int    `json:"locker_id"`
+	Kind     string `json:"kind"`
+}
+
+// 사물함 상태 (Locker.Status)
+const (
+	StatusFree         = "free"           // 소유자도 유효한 hold도 없음
+	StatusHeld         = "held"           // 누군가 hold 중 (아직 확정 전)
+	StatusTaken        = "taken"          // 확정되어 소유자가 있음
+	StatusOutOfService = "out_of_service" // 소유자 없이 고장/사용 중지 (hold 불가)
+)
+
+// 사물함 속성 값 (locker_attributes.size / row_position CHECK 제약과 같음)
+const (
+	SizeSmall  = "small"
+	SizeMedium = "medium"
+	SizeLarge  = "large"
+
+	RowTop    = "top"
+	RowMiddle = "middle"
+	RowBottom = "bottom"
+)
+
+// Sizes / RowPositions 허용 값 (작은 것 → 큰 것, 위 → 아래 순)
+var (
+	Sizes        = []string{SizeSmall, SizeMedium, SizeLarge}
+	RowPositions = []string{RowTop, RowMiddle, RowBottom}
+)
+
+// Locker 스냅샷의 사물함 한 건
+type Locker struct {
+	LockerID       int
+	LocationID     int
+	LocationName   string
+	OwnerStudentID *string
+	OwnerSerialID  *int64
+	HeldUntil      *time.Time // 유효한 hold의 만료 시각 (없으면 nil)
+
+	// locker_attributes (행이 없으면 nil/false)
+	Size         *string
+	RowPosition  *string
+	NearOutlet   bool
+	OutOfService bool
+
+	// 배치도 위치 (1부터, 배치되지 않았으면 nil)
+	GridRow *int
+	GridCol *int
+}
+
+// Status: now 기준 사물함 상태
+// - hold 만료는 스윕 작업이 이벤트를 보내기 전에도 반영되도록 조회 시점에 판단한다.
+func (l Locker) Status(now time.Time) string {
+	switch {
+	case l.OwnerSerialID != nil:
+		return StatusTaken
+	case l.OutOfService:
+		return StatusOutOfService
+	case l.HeldUntil != nil && l.HeldUntil.After(now):
+		return StatusHeld
+	default:
+		return StatusFree
+	}
+}
+
+// Location 스냅샷의 위치 한 건
+type Location struct {
+	LocationID int
+	Name       string
+	GridRows   *int // 배치도 격자 크기 (없으면 nil)
+	GridCols   *int
+}
+
+// Snapshot 사물함 목록 스냅샷 (읽기 전용으로 공유되므로 수정 금지)
+// - Lockers는 locker_id 오름차순 (커서 페이지네이션 기준)
+type Snapshot struct {
+	Lockers   []Locker
+	Locations []Location
+	ETag      string
+	BuiltAt   time.Time
+
+	generation uint64
+	nextExpiry *time.Time // 가장 먼저 끝나는 hold (이 시각이 지나면 상태/ETag가 바뀌므로 다시 만듦)
+}
+
+// Cache 사물함 목록 캐시
+type Cache struct {
+	db     *pgxpool.Pool
+	rdb    *redis.Client
+	maxAge time.Duration
+
+	buildMu    sync.Mutex
+	current    atomic.Pointer[Snapshot]
+	generation atomic.Uint64 // 무효화될 때마다 증가
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New: 캐시 생성 (Start로 이벤트 구독 시작)
+func New(db *pgxpool.Pool, rdb *redis.Client) *Cache {
+	return &Cache{
+		db:     db,
+		rdb:    rdb,
+		maxAge: time.Duration(util.EnvInt("LOCKER_CACHE_MAX_AGE_SEC", 30)) * time.Second,
+	}
+}
+
+// Get: 현재 스냅샷 (무효화됐거나 오래됐으면 DB에서 다시 만듦)
+func (c *Cache) Get(ctx context.Context) (*Snapshot, error) {
+	if snap := c.current.Load(); c.fresh(snap) {
+		return snap, nil
+	}
+
+	c.buildMu.Lock()
+	defer c.buildMu.Unlock()
+	// 기다리는 동안 다른 요청이 이미 만들었을 수 있음
+	if snap := c.current.Load(); c.fresh(snap) {
+		return snap, nil
+	}
+
+	snap, err := c.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.current.Store(snap)
+	return snap, nil
+}
+
+// Invalidate: 이 인스턴스의 스냅샷 무효화
+func (c *Cache) Invalidate() {
+	c.generation.Add(1)
+}
+
+// Publish: 사물함 상태 변경 알림 (이 인스턴스 즉시 무효화 + 다른 인스턴스에 전파)
+// - 전파 실패는 로그만 남긴다 (다른 인스턴스는 max age로 따라옴)
+func (c *Cache) Publish(ctx context.Context, lockerID int, kind string) {
+	c.Invalidate()
+	raw, _ := json.Marshal(Event{LockerID: lockerID, Kind: kind})
+	if err := c.rdb.Publish(ctx, Channel, raw).Err(); err != nil {
+		log.Printf("lockercache: failed to publish %s event for locker %d: %v", kind, lockerID, err)
+	}
+}
+
+// Start: 다른 인스턴스의 이벤트 구독 시작 (구독 실패 시 max age로만 갱신)
+func (c *Cache) Start(ctx context.Context) {
+	pubsub := c.rdb.Subscribe(ctx, Channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		log.Printf("lockercache: failed to subscribe to %s, falling back to max age %s: %v", Channel, c.maxAge, err)
+		_ = pubsub.Close()
+		return
+	}
+
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		defer pubsub.Close()
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+				c.Invalidate()
+			}
+		}
+	}()
+}
+
+// Stop: 구독 종료
+func (c *Cache) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	<-c.done
+}
+
+func (c *Cache) fresh(snap *Snapshot) bool {
+	return snap != nil &&
+		snap.generation == c.generation.Load() &&
+		time.Since(snap.BuiltAt) < c.maxAge &&
+		(snap.nextExpiry == nil || time.Now().Before(*snap.nextExpiry))
+}
+
+// build: DB에서 스냅샷 생성 (사물함 + 유효한 hold + 위치 목록)
+func (c *Cache) build(ctx context.Context) (*Snapshot, error) {
+	// 조회 전에 세대를 읽어 둔다 → 조회 중에 들어온 무효화는 다음 요청에서 다시 반영됨
+	gen := c.generation.Load()
+
+	snap := &Snapshot{Lockers: []Locker{}, Locations: []Location{}, BuiltAt: time.Now(), generation: gen}
+
+	// hold는 사물함당 최대 1건 (부분 유니크 인덱스)
+	rows, err := c.db.Query(ctx,
+		`SELECT l.locker_id, l.location_id, ll.name, l.owner_student_id, l.owner_serial_id,
+		        a.hold_expires_at::timestamptz,
+		        la.size, la.row_position, COALESCE(la.near_outlet, false), COALESCE(la.out_of_service, false),
+		        l.grid_row, l.grid_col
+		   FROM locker_info l
+		   JOIN locker_locations ll ON ll.location_id = l.location_id
+		   LEFT JOIN locker_assignments a
+		          ON a.locker_id = l.locker_id AND a.state = 'hold' AND a.hold_expires_at > now()
+		   LEFT JOIN locker_attributes la ON la.locker_id = l.locker_id
+		  ORDER BY l.locker_id`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var it Locker
+		if err := rows.Scan(&it.LockerID, &it.LocationID, &it.LocationName,
+			&it.OwnerStudentID, &it.OwnerSerialID, &it.HeldUntil,
+			&it.Size, &it.RowPosition, &it.NearOutlet, &it.OutOfService,
+			&it.GridRow, &it.GridCol); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if it.HeldUntil != nil && (snap.nextExpiry == nil || it.HeldUntil.Before(*snap.nextExpiry)) {
+			snap.nextExpiry = it.HeldUntil
+		}
+		snap.Lockers = append(snap.Lockers, it)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 위치 목록 (사물함이 없는 위치도 요약에 나오도록 따로 조회)
+	rows, err = c.db.Query(ctx, `SELECT location_id, name, grid_rows, grid_cols FROM locker_locations ORDER BY location_id`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var loc Location
+		if err := rows.Scan(&loc.LocationID, &loc.Name, &loc.GridRows, &loc.GridCols); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		snap.Locations = append(snap.Locations, loc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// ETag: 내용 해시 (내용이 같으면 다시 만들어도 같은 ETag → 304 유지)
+	raw, _ := json.Marshal(struct {
+		L []Locker
+		P []Location
+	}{snap.Lockers, snap.Locations})
+	sum := sha256.Sum256(raw)
+	snap.ETag = `"` + hex.EncodeToString(sum[:12]) + `"`
+	return snap, nil
+}
@@ -0,0 +1,419 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/holdstore"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/KUCSEPotato/locker-server/internal/round"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis hold 키(locker:hold:*, user:hold:*) ↔ locker_assignments ↔ locker_info 정합성 검사기
+//
+// 검사하는 불변식과 복구 방법:
+//   - orphan_redis_hold   : Redis 사물함 hold 키가 있는데 같은 사용자의 유효한 DB hold 행이 없음 → 키 삭제(소유자 확인)
+//   - orphan_user_key     : user:hold 키가 가리키는 사물함 hold가 이 사용자 것이 아님 → 사용자 키 삭제
+//   - stale_db_hold       : DB hold 행이 있는데 Redis 키가 없거나 다른 사용자 것 → expired 처리
+//   - expired_db_hold     : hold_expires_at이 지났는데 state='hold' 그대로 → expired 처리
+//   - missing_owner       : confirmed 배정이 있는데 locker_info.owner가 비어 있음 → owner 설정
+//   - conflicting_owner   : confirmed 배정의 사용자와 locker_info.owner가 다름 → 보고만 (수동 판단 필요)
+//   - orphan_owner        : locker_info.owner가 있는데 그 사용자의 confirmed 배정이 없음 → 보고만 (수동 판단 필요)
+//   - owner_student_mismatch : owner_student_id가 users.student_id와 다름 → owner_student_id 갱신
+//
+// HoldLocker는 Redis 키를 먼저 잡고 DB에 기록하므로, 방금 만들어진 키(남은 TTL이 회차 hold TTL - graceWindow 이상)는
+// 아직 DB 기록 중일 수 있어 orphan으로 보지 않는다.
+
+const (
+	KindOrphanRedisHold      = "orphan_redis_hold"
+	KindOrphanUserKey        = "orphan_user_key"
+	KindStaleDBHold          = "stale_db_hold"
+	KindExpiredDBHold        = "expired_db_hold"
+	KindMissingOwner         = "missing_owner"
+	KindConflictingOwner     = "conflicting_owner"
+	KindOrphanOwner          = "orphan_owner"
+	KindOwnerStudentMismatch = "owner_student_mismatch"
+)
+
+// 마지막 실행 결과를 저장하는 Redis 키 (여러 인스턴스에서 같은 결과를 보도록)
+const lastReportKey = "reconcile:last"
+
+// 방금 만들어진 Redis hold 키를 orphan으로 오판하지 않기 위한 유예 시간
+const graceWindow = 5 * time.Second
+
+// Finding 불일치 한 건
+type Finding struct {
+	Kind     string `json:"kind" example:"orphan_redis_hold"`
+	LockerID int    `json:"locker_id,omitempty" example:"101"`
+	SerialID int64  `json:"serial_id,omitempty" example:"123456789012"`
+	Detail   string `json:"detail" example:"redis hold without db hold row"`
+	Repaired bool   `json:"repaired"`
+}
+
+// Report 한 번의 검사 결과
+type Report struct {
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Repair     bool           `json:"repair"`
+	Findings   []Finding      `json:"findings"`
+	Counts     map[string]int `json:"counts"`
+	Errors     []string       `json:"errors,omitempty"`
+}
+
+// Reconciler 정합성 검사기
+type Reconciler struct {
+	db      *pgxpool.Pool
+	rdb     *redis.Client
+	holds   *holdstore.Store
+	lockers *lockercache.Cache
+}
+
+// New: 정합성 검사기 생성
+func New(db *pgxpool.Pool, rdb *redis.Client, holds *holdstore.Store, lockers *lockercache.Cache) *Reconciler {
+	return &Reconciler{db: db, rdb: rdb, holds: holds, lockers: lockers}
+}
+
+// Run: 모든 불변식을 검사. repair=true면 안전하게 고칠 수 있는 항목은 고친다.
+// 결과는 lastReportKey에 저장되어 LastReport로 다시 볼 수 있다.
+func (r *Reconciler) Run(ctx context.Context, repair bool) (*Report, error) {
+	rep := &Report{
+		StartedAt: time.Now(),
+		Repair:    repair,
+		Findings:  []Finding{},
+		Counts:    map[string]int{},
+	}
+
+	rd, err := round.Current(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1) Redis hold 키 ↔ DB hold 행
+	if err := r.checkHolds(ctx, rep, rd.HoldTTL, repair); err != nil {
+		return nil, err
+	}
+	// 2) user:hold 키 ↔ 사물함 hold 키
+	if err := r.checkUserKeys(ctx, rep, repair); err != nil {
+		return nil, err
+	}
+	// 3) confirmed 배정 ↔ locker_info.owner
+	if err := r.checkOwners(ctx, rep, repair); err != nil {
+		return nil, err
+	}
+
+	rep.FinishedAt = time.Now()
+	repaired := false
+	for _, f := range rep.Findings {
+		rep.Counts[f.Kind]++
+		repaired = repaired || f.Repaired
+	}
+	// 복구로 hold/owner가 바뀌었으면 사물함 목록 캐시 무효화
+	if repaired {
+		r.lockers.Publish(ctx, 0, lockercache.EventAdmin)
+	}
+
+	if raw, err := json.Marshal(rep); err == nil {
+		if err := r.rdb.Set(ctx, lastReportKey, raw, 0).Err(); err != nil {
+			log.Printf("reconcile: failed to store last report: %v", err)
+		}
+	}
+	return rep, nil
+}
+
+// LastReport: 마지막 실행 결과 (아직 실행된 적 없으면 nil)
+func (r *Reconciler) LastReport(ctx context.Context) (*Report, error) {
+	raw, err := r.rdb.Get(ctx, lastReportKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rep Report
+	if err := json.Unmarshal(raw, &rep); err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
+
+// add: 결과 추가 (에러는 보고서에 남기고 계속 진행)
+func (rep *Report) add(f Finding, repairErr error) {
+	if repairErr != nil {
+		rep.Errors = append(rep.Errors, f.Kind+": "+repairErr.Error())
+		f.Repaired = false
+	}
+	rep.Findings = append(rep.Findings, f)
+}
+
+// dbHold DB의 hold 행 한 건
+type dbHold struct {
+	lockerID int
+	serialID int64
+	expired  bool
+}
+
+func (r *Reconciler) checkHolds(ctx context.Context, rep *Report, holdTTL time.Duration, repair bool) error {
+	// DB hold 행 (locker_id 기준, 부분 유니크 인덱스로 사물함당 최대 1건)
+	rows, err := r.db.Query(ctx,
+		`SELECT locker_id, user_serial_id, (hold_expires_at IS NOT NULL AND hold_expires_at <= now())
+		   FROM locker_assignments WHERE state = 'hold'`)
+	if err != nil {
+		return err
+	}
+	holds := map[int]dbHold{}
+	for rows.Next() {
+		var h dbHold
+		if err := rows.Scan(&h.lockerID, &h.serialID, &h.expired); err != nil {
+			rows.Close()
+			return err
+		}
+		holds[h.lockerID] = h
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Redis 사물함 hold 키
+	seen := map[int]bool{}
+	iter := r.rdb.Scan(ctx, 0, holdstore.LockerKeyPattern, 500).Iterator()
+	for iter.Next(ctx) {
+		lockerID, ok := holdstore.ParseLockerKey(iter.Val())
+		if !ok {
+			continue
+		}
+		seen[lockerID] = true
+
+		holder, ttl, ok, err := r.holds.Holder(ctx, lockerID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue // SCAN 이후 만료됨
+		}
+		h, hasRow := holds[lockerID]
+		if hasRow && h.serialID == holder && !h.expired {
+			continue // 정상
+		}
+		if ttl > holdTTL-graceWindow {
+			continue // 방금 잡힌 hold → DB 기록 중일 수 있음
+		}
+
+		f := Finding{Kind: KindOrphanRedisHold, LockerID: lockerID, SerialID: holder,
+			Detail: "redis hold without matching db hold row"}
+		var repairErr error
+		if repair {
+			repairErr = r.holds.Release(ctx, lockerID, holder)
+			if errors.Is(repairErr, holdstore.ErrNotOwner) {
+				repairErr = nil // 그 사이 만료/해제됨
+			}
+			f.Repaired = repairErr == nil
+		}
+		rep.add(f, repairErr)
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	// DB hold 행 쪽에서 본 불일치
+	for lockerID, h := range holds {
+		kind, detail := "", ""
+		switch {
+		case h.expired:
+			kind, detail = KindExpiredDBHold, "hold_expires_at passed but state is still hold"
+		case !seen[lockerID]:
+			kind, detail = KindStaleDBHold, "db hold row without redis hold key"
+		default:
+			holder, _, ok, err := r.holds.Holder(ctx, lockerID)
+			if err != nil {
+				return err
+			}
+			if ok && holder == h.serialID {
+				continue
+			}
+			kind, detail = KindStaleDBHold, "redis hold key belongs to another user"
+		}
+
+		f := Finding{Kind: kind, LockerID: lockerID, SerialID: h.serialID, Detail: detail}
+		var repairErr error
+		if repair {
+			repairErr = r.repairDB(ctx, f,
+				`UPDATE locker_assignments SET state = 'expired'
+				  WHERE locker_id = $1 AND user_serial_id = $2 AND state = 'hold'`,
+				lockerID, h.serialID)
+			f.Repaired = repairErr == nil
+		}
+		rep.add(f, repairErr)
+	}
+	return nil
+}
+
+func (r *Reconciler) checkUserKeys(ctx context.Context, rep *Report, repair bool) error {
+	iter := r.rdb.Scan(ctx, 0, holdstore.UserKeyPattern, 500).Iterator()
+	for iter.Next(ctx) {
+		serialID, ok := holdstore.ParseUserKey(iter.Val())
+		if !ok {
+			continue
+		}
+		lockerID, err := r.rdb.Get(ctx, iter.Val()).Int()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		holder, _, ok, err := r.holds.Holder(ctx, lockerID)
+		if err != nil {
+			return err
+		}
+		if ok && holder == serialID {
+			continue
+		}
+
+		f := Finding{Kind: KindOrphanUserKey, LockerID: lockerID, SerialID: serialID,
+			Detail: "user hold key points to a locker not held by this user"}
+		var repairErr error
+		if repair {
+			// 삭제 직전에 다시 확인 (그 사이 같은 사물함을 다시 잡았을 수 있음)
+			_, repairErr = r.holds.DropStaleUserKey(ctx, serialID)
+			f.Repaired = repairErr == nil
+		}
+		rep.add(f, repairErr)
+	}
+	return iter.Err()
+}
+
+func (r *Reconciler) checkOwners(ctx context.Context, rep *Report, repair bool) error {
+	// confirmed 배정 기준: owner 없음 / 다른 owner
+	rows, err := r.db.Query(ctx,
+		`SELECT a.locker_id, a.user_serial_id, l.owner_serial_id
+		   FROM locker_assignments a
+		   JOIN locker_info l ON l.locker_id = a.locker_id
+		  WHERE a.state = 'confirmed'
+		    AND (l.owner_serial_id IS NULL OR l.owner_serial_id <> a.user_serial_id)`)
+	if err != nil {
+		return err
+	}
+	type pair struct {
+		lockerID int
+		serialID int64
+		owner    *int64
+	}
+	var mismatched []pair
+	for rows.Next() {
+		var p pair
+		if err := rows.Scan(&p.lockerID, &p.serialID, &p.owner); err != nil {
+			rows.Close()
+			return err
+		}
+		mismatched = append(mismatched, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, p := range mismatched {
+		if p.owner != nil {
+			rep.add(Finding{Kind: KindConflictingOwner, LockerID: p.lockerID, SerialID: p.serialID,
+				Detail: "confirmed assignment user differs from locker_info owner"}, nil)
+			continue
+		}
+		f := Finding{Kind: KindMissingOwner, LockerID: p.lockerID, SerialID: p.serialID,
+			Detail: "confirmed assignment without locker_info owner"}
+		var repairErr error
+		if repair {
+			repairErr = r.repairDB(ctx, f,
+				`UPDATE locker_info l
+				    SET owner_serial_id = u.serial_id, owner_student_id = u.student_id
+				   FROM users u
+				  WHERE l.locker_id = $1 AND l.owner_serial_id IS NULL AND u.serial_id = $2`,
+				p.lockerID, p.serialID)
+			f.Repaired = repairErr == nil
+		}
+		rep.add(f, repairErr)
+	}
+
+	// owner 기준: confirmed 배정 없음 / owner_student_id 불일치
+	rows, err = r.db.Query(ctx,
+		`SELECT l.locker_id, l.owner_serial_id,
+		        NOT EXISTS (SELECT 1 FROM locker_assignments a
+		                     WHERE a.locker_id = l.locker_id AND a.user_serial_id = l.owner_serial_id
+		                       AND a.state = 'confirmed'),
+		        l.owner_student_id IS DISTINCT FROM u.student_id
+		   FROM locker_info l
+		   JOIN users u ON u.serial_id = l.owner_serial_id
+		  WHERE l.owner_serial_id IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+	type ownerRow struct {
+		lockerID        int
+		serialID        int64
+		noAssignment    bool
+		studentMismatch bool
+	}
+	var owners []ownerRow
+	for rows.Next() {
+		var o ownerRow
+		if err := rows.Scan(&o.lockerID, &o.serialID, &o.noAssignment, &o.studentMismatch); err != nil {
+			rows.Close()
+			return err
+		}
+		if o.noAssignment || o.studentMismatch {
+			owners = append(owners, o)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, o := range owners {
+		if o.noAssignment {
+			rep.add(Finding{Kind: KindOrphanOwner, LockerID: o.lockerID, SerialID: o.serialID,
+				Detail: "locker_info owner without confirmed assignment"}, nil)
+		}
+		if o.studentMismatch {
+			f := Finding{Kind: KindOwnerStudentMismatch, LockerID: o.lockerID, SerialID: o.serialID,
+				Detail: "owner_student_id differs from users.student_id"}
+			var repairErr error
+			if repair {
+				repairErr = r.repairDB(ctx, f,
+					`UPDATE locker_info l SET owner_student_id = u.student_id
+					   FROM users u
+					  WHERE l.locker_id = $1 AND l.owner_serial_id = $2 AND u.serial_id = l.owner_serial_id`,
+					o.lockerID, o.serialID)
+				f.Repaired = repairErr == nil
+			}
+			rep.add(f, repairErr)
+		}
+	}
+	return nil
+}
+
+// repairDB: 복구 UPDATE 한 건 + 감사 로그 (같은 트랜잭션)
+func (r *Reconciler) repairDB(ctx context.Context, f Finding, sql string, args ...any) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	ct, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() > 0 {
+		if err := audit.Record(ctx, tx, audit.System(audit.ReconcileRepair, audit.TargetLocker, strconv.Itoa(f.LockerID)).
+			Change(nil, f)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,186 @@
+package round
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/eligibility"
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// 신청 회차(round) 설정
+// - rounds 테이블에서 is_active=true 인 회차를 읽는다.
+// - 활성 회차가 없으면 환경변수로 만든 기본 회차(ID=0)를 돌려준다 (기존 배포 호환).
+//   LOCKER_APPLICATION_START / LOCKER_APPLICATION_END (RFC3339)
+//   HOLD_TTL_SEC (기본 60), HOLD_MAX_EXTENSIONS (기본 1)
+//   LOCKER_DIRECT_CONFIRM (기본 false)
+//   신청 자격: eligibility.FromEnv 참고
+// - hold TTL은 Redis 키와 DB hold_expires_at 모두 Round.HoldTTL 하나만 사용한다.
+// - 우선 순위 그룹(round_priority_groups)이 있으면 학생마다 신청 시작 시각이 다르다 → ScheduleFor
+
+// Round 회차 설정
+type Round struct {
+	ID            int64
+	Name          string
+	OpensAt       *time.Time // nil이면 시작 제한 없음
+	ClosesAt      *time.Time // nil이면 마감 제한 없음
+	HoldTTL       time.Duration
+	MaxExtensions int
+	DirectConfirm bool // true면 hold 없이 바로 확정(claim) 허용
+	Eligibility   eligibility.Rules
+	Groups        []PriorityGroup // 우선 순위 그룹 (opens_at 순)
+}
+
+// PriorityGroup 먼저 신청할 수 있는 학생 그룹
+type PriorityGroup struct {
+	ID      int64
+	Name    string
+	OpensAt time.Time
+	Rules   eligibility.Rules // 그룹 조건 (비어 있으면 모든 학생)
+}
+
+// Schedule 학생 한 명의 신청 일정
+type Schedule struct {
+	Group    *PriorityGroup // 적용된 우선 순위 그룹 (nil이면 일반)
+	OpensAt  *time.Time     // nil이면 시작 제한 없음
+	ClosesAt *time.Time     // nil이면 마감 제한 없음
+}
+
+// NotOpenYet: now가 이 학생의 신청 시작 전인지
+func (s Schedule) NotOpenYet(now time.Time) bool {
+	return s.OpensAt != nil && now.Before(*s.OpensAt)
+}
+
+// Closed: now가 신청 마감 후인지
+func (s Schedule) Closed(now time.Time) bool {
+	return s.ClosesAt != nil && now.After(*s.ClosesAt)
+}
+
+// ScheduleFor: 학생이 속한 우선 순위 그룹 중 가장 이른 시작 시각 (일반 시작보다 늦은 그룹은 무시)
+func (r *Round) ScheduleFor(st eligibility.Student) Schedule {
+	s := Schedule{OpensAt: r.OpensAt, ClosesAt: r.ClosesAt}
+	if r.OpensAt == nil {
+		return s
+	}
+	for i := range r.Groups {
+		g := &r.Groups[i]
+		if !g.OpensAt.Before(*s.OpensAt) {
+			break
+		}
+		if g.Rules.Match(st, "").Eligible {
+			opensAt := g.OpensAt
+			s.Group, s.OpensAt = g, &opensAt
+			break
+		}
+	}
+	return s
+}
+
+// NeedsStudent: 자격 규칙이나 우선 순위 그룹이 있어 학생 정보(eligibility.Lookup)가 필요한지
+func (r *Round) NeedsStudent() bool {
+	return !r.Eligibility.Empty() || len(r.Groups) > 0
+}
+
+// HoldTTLSeconds: SQL(now() + $n * interval '1 second')에 넘길 초 단위 TTL
+func (r *Round) HoldTTLSeconds() int {
+	return int(r.HoldTTL / time.Second)
+}
+
+// NotOpenYet: now가 신청 시작 전인지
+func (r *Round) NotOpenYet(now time.Time) bool {
+	return r.OpensAt != nil && now.Before(*r.OpensAt)
+}
+
+// Closed: now가 신청 마감 후인지
+func (r *Round) Closed(now time.Time) bool {
+	return r.ClosesAt != nil && now.After(*r.ClosesAt)
+}
+
+// Current: 현재 활성 회차 조회 (없으면 환경변수 기본값)
+func Current(ctx context.Context, db *pgxpool.Pool) (*Round, error) {
+	var (
+		r                Round
+		ttlSec           int
+		yearMin, yearMax *int
+	)
+	err := db.QueryRow(ctx,
+		`SELECT round_id, name, opens_at, closes_at, hold_ttl_sec, max_hold_extensions, direct_confirm,
+		        eligibility_roster, eligibility_fee_paid, COALESCE(eligible_id_prefixes, '{}'), eligible_year_min, eligible_year_max
+		   FROM rounds WHERE is_active`,
+	).Scan(&r.ID, &r.Name, &r.OpensAt, &r.ClosesAt, &ttlSec, &r.MaxExtensions, &r.DirectConfirm,
+		&r.Eligibility.Roster, &r.Eligibility.FeePaid, &r.Eligibility.Prefixes, &yearMin, &yearMax)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fromEnv(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.HoldTTL = time.Duration(ttlSec) * time.Second
+	if yearMin != nil {
+		r.Eligibility.YearMin = *yearMin
+	}
+	if yearMax != nil {
+		r.Eligibility.YearMax = *yearMax
+	}
+	if r.Groups, err = loadGroups(ctx, db, r.ID); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// loadGroups: 회차의 우선 순위 그룹 (opens_at 순)
+func loadGroups(ctx context.Context, db *pgxpool.Pool, roundID int64) ([]PriorityGroup, error) {
+	rows, err := db.Query(ctx,
+		`SELECT group_id, name, opens_at, roster, fee_paid, COALESCE(id_prefixes, '{}'),
+		        COALESCE(year_min, 0), COALESCE(year_max, 0)
+		   FROM round_priority_groups
+		  WHERE round_id = $1
+		  ORDER BY opens_at, group_id`,
+		roundID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []PriorityGroup
+	for rows.Next() {
+		var g PriorityGroup
+		if err := rows.Scan(&g.ID, &g.Name, &g.OpensAt, &g.Rules.Roster, &g.Rules.FeePaid, &g.Rules.Prefixes,
+			&g.Rules.YearMin, &g.Rules.YearMax); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// fromEnv: 환경변수 기반 기본 회차
+func fromEnv() *Round {
+	r := &Round{
+		Name:          "default",
+		HoldTTL:       time.Duration(util.EnvInt("HOLD_TTL_SEC", 60)) * time.Second,
+		MaxExtensions: util.EnvInt("HOLD_MAX_EXTENSIONS", 1),
+		DirectConfirm: util.EnvBool("LOCKER_DIRECT_CONFIRM", false),
+		Eligibility:   eligibility.FromEnv(),
+	}
+	r.OpensAt = envTime("LOCKER_APPLICATION_START")
+	r.ClosesAt = envTime("LOCKER_APPLICATION_END")
+	return r
+}
+
+// envTime: RFC3339 환경변수 파싱 (비어 있거나 형식 오류면 nil → 제한 없음)
+func envTime(key string) *time.Time {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/audit"
+	"github.com/KUCSEPotato/locker-server/internal/holdstore"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// hold 만료 처리 (Postgres hold_expires_at 기준)
+// - state='hold' AND hold_expires_at <= now() 인 행을 FOR UPDATE SKIP LOCKED로 가져가 expired로 바꾼다.
+//   UPDATE가 상태를 바꾸므로 같은 행이 두 번 처리되는 일은 없다 (여러 인스턴스가 동시에 돌아도 안전).
+// - 만료마다 감사 로그(locker.hold_expire, 시스템)를 같은 트랜잭션에 남긴다.
+// - 처리한 hold의 Redis 키는 소유자 확인 후 정리 (보통은 TTL로 이미 사라져 있음), 사물함 목록 캐시에도 알림
+// - Redis keyspace 알림(realtime_cleanup.go)은 선택적인 가속 장치일 뿐, 이 작업만으로 만료가 보장된다.
+
+// 한 번의 UPDATE로 처리하는 최대 행 수
+const expiryBatchSize = 500
+
+// ExpiryJob 만료된 hold 처리 작업
+// - HOLD_EXPIRY_SWEEP_SEC (기본 2초) 마다 실행
+func ExpiryJob(db *pgxpool.Pool, holds *holdstore.Store, lockers *lockercache.Cache) Job {
+	return Job{
+		Name:     "hold-expiry-sweep",
+		Interval: time.Duration(util.EnvInt("HOLD_EXPIRY_SWEEP_SEC", 2)) * time.Second,
+		Timeout:  10 * time.Second,
+		Run: func(ctx context.Context) error {
+			n, err := ExpireDueHolds(ctx, db, holds, lockers)
+			if n > 0 {
+				log.Printf("Expired %d holds", n)
+			}
+			return err
+		},
+	}
+}
+
+// ExpireDueHolds 만료 시각이 지난 hold를 모두 expired로 변경 (배치 단위로 반복)
+func ExpireDueHolds(ctx context.Context, db *pgxpool.Pool, holds *holdstore.Store, lockers *lockercache.Cache) (int, error) {
+	total := 0
+	for {
+		batch, err := expireBatch(ctx, db)
+		if err != nil {
+			return total, err
+		}
+
+		// Redis 키 정리 (베스트 에포트, 소유자 확인 → 그 사이 다른 사람이 잡은 hold는 건드리지 않음)
+		for _, e := range batch {
+			_ = holds.Release(ctx, e.lockerID, e.serialID)
+			lockers.Publish(ctx, e.lockerID, lockercache.EventExpire)
+		}
+
+		total += len(batch)
+		if len(batch) < expiryBatchSize {
+			return total, nil
+		}
+	}
+}
+
+type expiredHold struct {
+	lockerID  int
+	serialID  int64
+	expiresAt time.Time
+}
+
+// expireBatch: 만료된 hold 최대 expiryBatchSize개를 expired로 바꾸고 감사 로그를 같은 트랜잭션에 기록
+func expireBatch(ctx context.Context, db *pgxpool.Pool) ([]expiredHold, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`WITH due AS (
+		     SELECT assignment_id
+		       FROM locker_assignments
+		      WHERE state = 'hold' AND hold_expires_at <= now()
+		      ORDER BY hold_expires_at
+		      LIMIT $1
+		      FOR UPDATE SKIP LOCKED
+		 )
+		 UPDATE locker_assignments a
+		    SET state = 'expired'
+		   FROM due
+		  WHERE a.assignment_id = due.assignment_id
+		RETURNING a.locker_id, a.user_serial_id, a.hold_expires_at::timestamptz`,
+		expiryBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	var batch []expiredHold
+	for rows.Next() {
+		var e expiredHold
+		if err := rows.Scan(&e.lockerID, &e.serialID, &e.expiresAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		batch = append(batch, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, e := range batch {
+		if err := recordExpired(ctx, tx, e.lockerID, e.serialID, &e.expiresAt); err != nil {
+			return nil, err
+		}
+	}
+	return batch, tx.Commit(ctx)
+}
+
+// recordExpired: hold 만료 감사 로그 (시스템)
+func recordExpired(ctx context.Context, tx pgx.Tx, lockerID int, serialID int64, expiresAt *time.Time) error {
+	return audit.Record(ctx, tx, audit.System(audit.LockerHoldExpire, audit.TargetLocker, strconv.Itoa(lockerID)).
+		Change(map[string]any{"state": "hold", "user_serial_id": serialID, "hold_expires_at": expiresAt},
+			map[string]any{"state": "expired"}))
+}
@@ -2,72 +2,111 @@ package scheduler
 
 import (
 	"context"
-	"fmt"
 	"log"
-	"strconv"
-	"strings"
+	"sync/atomic"
 
+	"github.com/KUCSEPotato/locker-server/internal/holdstore"
+	"github.com/KUCSEPotato/locker-server/internal/lockercache"
+	"github.com/KUCSEPotato/locker-server/internal/util"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-// StartRealtimeCleanup Redis keyspace notifications를 사용한 실시간 cleanup
-func StartRealtimeCleanup(db *pgxpool.Pool, rdb *redis.Client) {
-	// Redis keyspace notifications 활성화
-	_, err := rdb.ConfigSet(context.Background(), "notify-keyspace-events", "Ex").Result()
-	if err != nil {
-		log.Printf("Failed to enable Redis keyspace notifications: %v", err)
+// ExpiryListener Redis keyspace notifications를 사용한 실시간 cleanup (선택 사항)
+// - 만료 처리의 기준은 ExpiryJob(Postgres hold_expires_at)이고, 이 리스너는 사물함을 조금 더 빨리 풀어주는 가속 장치다.
+// - HOLD_EXPIRY_KEYSPACE_EVENTS=true 일 때만 구독한다. CONFIG SET은 하지 않으므로 Redis에 notify-keyspace-events "Ex"가 미리 설정되어 있어야 한다.
+// - 알림을 받으면 ExpireDueHolds를 바로 한 번 돌린다. Redis 키가 없다는 것만으로 hold를 만료시키지 않는다 (hold_expires_at 기준).
+// - 알림은 유실될 수 있고 모든 구독자에게 전달되므로, 리더 인스턴스(runner.IsLeader)만 처리한다.
+// - Stop을 호출하거나 Start에 넘긴 ctx가 끝나면 구독을 닫는다. 동작 여부는 Alive()로 헬스 체크에 노출
+type ExpiryListener struct {
+	db      *pgxpool.Pool
+	rdb     *redis.Client
+	holds   *holdstore.Store
+	lockers *lockercache.Cache
+	runner  *Runner
+	enabled bool
+	alive   atomic.Bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewExpiryListener: 리스너 생성 (HOLD_EXPIRY_KEYSPACE_EVENTS로 사용 여부 결정)
+func NewExpiryListener(db *pgxpool.Pool, rdb *redis.Client, holds *holdstore.Store, lockers *lockercache.Cache, runner *Runner) *ExpiryListener {
+	return &ExpiryListener{
+		db:      db,
+		rdb:     rdb,
+		holds:   holds,
+		lockers: lockers,
+		runner:  runner,
+		enabled: util.EnvBool("HOLD_EXPIRY_KEYSPACE_EVENTS", false),
+	}
+}
+
+// Enabled: 설정으로 켜져 있는지
+func (l *ExpiryListener) Enabled() bool {
+	return l.enabled
+}
+
+// Alive: 구독 goroutine이 동작 중인지
+func (l *ExpiryListener) Alive() bool {
+	return l.alive.Load()
+}
+
+// Start: expire 이벤트 구독 시작. 구독 실패는 로그만 남기고 넘어간다 (ExpiryJob이 만료를 보장)
+func (l *ExpiryListener) Start(ctx context.Context) {
+	if !l.enabled {
 		return
 	}
 
 	// expire 이벤트 구독
-	pubsub := rdb.PSubscribe(context.Background(), "__keyevent@0__:expired")
-	defer pubsub.Close()
+	pubsub := l.rdb.PSubscribe(ctx, "__keyevent@0__:expired")
+	if _, err := pubsub.Receive(ctx); err != nil {
+		log.Printf("Real-time cleanup disabled: failed to subscribe to Redis key expiration events: %v", err)
+		_ = pubsub.Close()
+		return
+	}
 
 	log.Println("Real-time cleanup started: listening for Redis key expiration events")
 
+	ctx, l.cancel = context.WithCancel(ctx)
+	l.done = make(chan struct{})
+	l.alive.Store(true)
 	go func() {
-		for msg := range pubsub.Channel() {
-			// 메시지 형태: "locker:hold:101"
-			if strings.HasPrefix(msg.Payload, "locker:hold:") {
-				// locker ID 추출
-				parts := strings.Split(msg.Payload, ":")
-				if len(parts) == 3 {
-					lockerIDStr := parts[2]
-					lockerID, err := strconv.Atoi(lockerIDStr)
-					if err != nil {
-						log.Printf("Invalid locker ID in expired key: %s", msg.Payload)
-						continue
-					}
-
-					// DB에서 해당 locker의 hold 상태를 expired로 업데이트
-					if err := markHoldAsExpired(db, lockerID); err != nil {
-						log.Printf("Failed to mark locker %d as expired: %v", lockerID, err)
-					} else {
-						log.Printf("Successfully marked locker %d hold as expired (real-time)", lockerID)
-					}
+		defer close(l.done)
+		defer l.alive.Store(false)
+		defer pubsub.Close()
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				if !l.runner.IsLeader() {
+					continue
+				}
+				// 메시지 형태: "locker:hold:101" (user:hold:* 만료는 무시)
+				lockerID, ok := holdstore.ParseLockerKey(msg.Payload)
+				if !ok {
+					continue
+				}
+				// DB에서 만료 시각이 지난 hold만 정리 (Redis 키가 유실됐거나 그 사이 다시 잡힌 hold는 유지)
+				if _, err := ExpireDueHolds(ctx, l.db, l.holds, l.lockers); err != nil {
+					log.Printf("Failed to expire holds after locker %d key expired: %v", lockerID, err)
 				}
 			}
 		}
 	}()
 }
 
-// markHoldAsExpired 특정 locker의 hold 상태를 expired로 변경
-func markHoldAsExpired(db *pgxpool.Pool, lockerID int) error {
-	query := `
-		UPDATE locker_assignments 
-		SET state = 'expired' 
-		WHERE locker_id = $1 AND state = 'hold'`
-
-	result, err := db.Exec(context.Background(), query, lockerID)
-	if err != nil {
-		return fmt.Errorf("failed to update locker %d: %w", lockerID, err)
-	}
-
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		log.Printf("No hold record found for locker %d (may have been already processed)", lockerID)
+// Stop: 구독을 닫고 goroutine이 끝날 때까지 대기
+func (l *ExpiryListener) Stop() {
+	if l.cancel == nil {
+		return
 	}
-
-	return nil
+	l.cancel()
+	<-l.done
 }
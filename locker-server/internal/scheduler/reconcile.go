@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/reconcile"
+	"github.com/KUCSEPotato/locker-server/internal/util"
+)
+
+// ReconcileJob Redis hold ↔ DB 배정 ↔ locker_info 정합성을 주기적으로 검사
+// - RECONCILE_INTERVAL_SEC (기본 60초) 마다 실행
+// - RECONCILE_REPAIR=false 면 보고만 하고 고치지 않음 (기본 true)
+func ReconcileJob(rec *reconcile.Reconciler) Job {
+	interval := time.Duration(util.EnvInt("RECONCILE_INTERVAL_SEC", 60)) * time.Second
+	repair := util.EnvBool("RECONCILE_REPAIR", true)
+
+	return Job{
+		Name:     "reconcile",
+		Interval: interval,
+		Timeout:  30 * time.Second,
+		Run: func(ctx context.Context) error {
+			rep, err := rec.Run(ctx, repair)
+			if err != nil {
+				return err
+			}
+			if len(rep.Findings) > 0 {
+				log.Printf("Reconcile found %d discrepancies (repair=%t): %v", len(rep.Findings), repair, rep.Counts)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,275 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KUCSEPotato/locker-server/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// 백그라운드 작업 실행기 (리더 선출 포함)
+// - 여러 인스턴스(replica)가 떠 있어도 Postgres advisory lock(scheduler:leader)을 가진 한 인스턴스만 작업을 실행한다.
+//   Redis가 죽어도 리더가 유지되므로 DB만 쓰는 작업(ExpiryJob 등)은 degraded 모드에서도 계속 돈다.
+// - lock은 풀에서 떼어 낸 전용 연결(세션)에 걸리고, SCHEDULER_LEASE_TTL_SEC(기본 15초)/3 마다 연결을 확인한다.
+//   리더가 죽거나 연결이 끊기면 Postgres가 lock을 풀고 다른 인스턴스가 이어받는다.
+// - 작업은 이름/주기/타임아웃으로 등록하고, panic은 복구해서 실패로 기록한다.
+// - 마지막 실행 상태는 Status()로 조회 (헬스 체크에서 노출)
+
+// advisory lock 키 (hashtext로 정수 키를 만든다)
+const leaderKey = "scheduler:leader"
+
+// Job 주기 작업
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Timeout  time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// JobStatus 작업별 마지막 실행 상태
+type JobStatus struct {
+	Name           string     `json:"name" example:"expired-hold-cleanup"`
+	IntervalSec    int        `json:"interval_sec" example:"10"`
+	Running        bool       `json:"running"`
+	Runs           int64      `json:"runs" example:"42"`
+	Failures       int64      `json:"failures" example:"0"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms" example:"12"`
+	LastError      string     `json:"last_error,omitempty"`
+}
+
+// RunnerStatus 실행기 상태 (헬스 체크용)
+type RunnerStatus struct {
+	InstanceID string      `json:"instance_id" example:"locker-api-1:4821:9f3a"`
+	Leader     bool        `json:"leader"`
+	Jobs       []JobStatus `json:"jobs"`
+}
+
+// Runner 리더 선출 기반 작업 실행기
+type Runner struct {
+	db         *pgxpool.Pool
+	instanceID string
+	leaseTTL   time.Duration
+	leader     atomic.Bool
+	leaseConn  *pgx.Conn // advisory lock을 잡고 있는 전용 연결 (리더일 때만, 리더 선출 goroutine만 접근)
+
+	mu     sync.Mutex
+	jobs   []Job
+	status map[string]*JobStatus
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRunner: 작업 실행기 생성 (Start 전에 Register로 작업 등록)
+func NewRunner(db *pgxpool.Pool) *Runner {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 2)
+	_, _ = rand.Read(suffix)
+	return &Runner{
+		db:         db,
+		instanceID: fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(suffix)),
+		leaseTTL:   time.Duration(util.EnvInt("SCHEDULER_LEASE_TTL_SEC", 15)) * time.Second,
+		status:     map[string]*JobStatus{},
+	}
+}
+
+// Register: 작업 등록
+func (r *Runner) Register(job Job) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, job)
+	r.status[job.Name] = &JobStatus{Name: job.Name, IntervalSec: int(job.Interval / time.Second)}
+}
+
+// IsLeader: 이 인스턴스가 현재 리더인지 (리스너 등 작업 외 코드도 이걸로 게이트)
+func (r *Runner) IsLeader() bool {
+	return r.leader.Load()
+}
+
+// Start: 리더 선출 루프와 등록된 작업 루프 시작
+func (r *Runner) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	r.renewLease(ctx)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.leaseTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.renewLease(ctx)
+			}
+		}
+	}()
+
+	r.mu.Lock()
+	jobs := append([]Job(nil), r.jobs...)
+	r.mu.Unlock()
+	for _, job := range jobs {
+		r.wg.Add(1)
+		go r.loop(ctx, job)
+	}
+	log.Printf("Scheduler started: instance %s, %d jobs, lease %s", r.instanceID, len(jobs), r.leaseTTL)
+}
+
+// Stop: 작업 루프 종료 대기 후 리더 lease 반납 (다른 인스턴스가 바로 이어받도록)
+func (r *Runner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+	r.leader.Store(false)
+	if r.leaseConn != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if _, err := r.leaseConn.Exec(ctx, `SELECT pg_advisory_unlock(hashtext($1))`, leaderKey); err != nil {
+			log.Printf("Scheduler: failed to resign leadership: %v", err)
+		}
+		r.dropLease(ctx)
+	}
+	log.Printf("Scheduler stopped: instance %s", r.instanceID)
+}
+
+// Status: 리더 여부 + 작업별 마지막 실행 상태
+func (r *Runner) Status() RunnerStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := RunnerStatus{InstanceID: r.instanceID, Leader: r.IsLeader(), Jobs: make([]JobStatus, 0, len(r.status))}
+	for _, st := range r.status {
+		out.Jobs = append(out.Jobs, *st)
+	}
+	sort.Slice(out.Jobs, func(i, j int) bool { return out.Jobs[i].Name < out.Jobs[j].Name })
+	return out
+}
+
+// renewLease: lease 획득/확인. 연결 오류면 안전하게 리더를 내려놓는다 (중복 실행 방지)
+func (r *Runner) renewLease(ctx context.Context) {
+	isLeader, err := r.tryLease(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Scheduler: lease renewal failed: %v", err)
+	}
+	if was := r.leader.Swap(isLeader); was != isLeader {
+		if isLeader {
+			log.Printf("Scheduler: instance %s became leader", r.instanceID)
+		} else {
+			log.Printf("Scheduler: instance %s lost leadership", r.instanceID)
+		}
+	}
+}
+
+// tryLease: 리더면 lock 연결이 살아 있는지 확인, 아니면 pg_try_advisory_lock으로 획득 시도
+// - lock을 잡으면 그 연결을 풀에서 떼어 내(Hijack) 계속 들고 있는다 (연결이 닫히면 lock도 풀림)
+func (r *Runner) tryLease(ctx context.Context) (bool, error) {
+	checkCtx, cancel := context.WithTimeout(ctx, r.leaseTTL/3)
+	defer cancel()
+
+	if r.leaseConn != nil {
+		if err := r.leaseConn.Ping(checkCtx); err != nil {
+			r.dropLease(ctx)
+			return false, err
+		}
+		return true, nil
+	}
+
+	conn, err := r.db.Acquire(checkCtx)
+	if err != nil {
+		return false, err
+	}
+	var ok bool
+	if err := conn.QueryRow(checkCtx, `SELECT pg_try_advisory_lock(hashtext($1))`, leaderKey).Scan(&ok); err != nil {
+		conn.Release()
+		return false, err
+	}
+	if !ok {
+		conn.Release()
+		return false, nil
+	}
+	r.leaseConn = conn.Hijack()
+	return true, nil
+}
+
+// dropLease: lock 연결을 닫는다 (세션이 끝나면 Postgres가 advisory lock을 푼다)
+func (r *Runner) dropLease(ctx context.Context) {
+	closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+	defer cancel()
+	_ = r.leaseConn.Close(closeCtx)
+	r.leaseConn = nil
+}
+
+// loop: 작업 하나의 주기 실행 루프 (리더일 때만 실행)
+func (r *Runner) loop(ctx context.Context, job Job) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.IsLeader() {
+				r.runOnce(ctx, job)
+			}
+		}
+	}
+}
+
+// runOnce: 타임아웃 + panic 복구 하에 작업 1회 실행하고 상태 기록
+func (r *Runner) runOnce(ctx context.Context, job Job) {
+	started := time.Now()
+	r.update(job.Name, func(st *JobStatus) {
+		st.Running = true
+		st.LastStartedAt = &started
+	})
+
+	err := func() (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				log.Printf("Scheduler: job %s panicked: %v\n%s", job.Name, p, debug.Stack())
+				err = fmt.Errorf("panic: %v", p)
+			}
+		}()
+		jobCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+		return job.Run(jobCtx)
+	}()
+
+	finished := time.Now()
+	r.update(job.Name, func(st *JobStatus) {
+		st.Running = false
+		st.Runs++
+		st.LastFinishedAt = &finished
+		st.LastDurationMs = finished.Sub(started).Milliseconds()
+		st.LastError = ""
+		if err != nil {
+			st.Failures++
+			st.LastError = err.Error()
+		}
+	})
+	if err != nil {
+		log.Printf("Scheduler: job %s failed: %v", job.Name, err)
+	}
+}
+
+func (r *Runner) update(name string, fn func(st *JobStatus)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(r.status[name])
+}
@@ -0,0 +1,103 @@
+package serial
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// 사용자 serial_id(12자리 숫자) 발급기
+// - 이전 방식(generateCustomSerial): SHA256(학번+이름+전화번호+"ku_info") 상위 8바이트 mod 10^12
+//   → salt가 코드에 박혀 있어 누구나 남의 serial_id를 계산할 수 있고, 충돌 시 INSERT가 실패해 500이 났다.
+// - 새 방식: HMAC-SHA256(USER_SERIAL_SECRET, 학번|이름|전화번호|시도번호) 상위 8바이트 mod 10^12
+//   → 비밀키 없이는 계산 불가, 충돌(users_pkey 위반)이 나면 시도번호를 올려 다른 후보로 재시도.
+//
+// 스킴 버전 (users.serial_scheme)
+// - SchemeLegacySHA256: 008 마이그레이션 이전에 만들어진 계정 (값은 그대로 유효, 로그인은 (학번, 이름, 전화번호)로 조회)
+// - SchemeHMAC: 이 발급기로 만든 계정
+
+const (
+	SchemeLegacySHA256 = 1
+	SchemeHMAC         = 2
+)
+
+// 12자리 숫자 공간 (0 ~ 999,999,999,999)
+const space uint64 = 1_000_000_000_000
+
+// ErrNoSecret USER_SERIAL_SECRET 미설정
+var ErrNoSecret = errors.New("USER_SERIAL_SECRET is not set")
+
+// ErrExhausted 모든 시도에서 충돌 (사실상 일어나지 않아야 함)
+var ErrExhausted = errors.New("serial id allocation exhausted all attempts")
+
+// Allocator serial_id 후보 생성기
+type Allocator struct {
+	secret      []byte
+	maxAttempts int
+}
+
+// NewAllocator: 비밀키와 최대 시도 횟수로 발급기 생성
+func NewAllocator(secret []byte, maxAttempts int) (*Allocator, error) {
+	if len(secret) == 0 {
+		return nil, ErrNoSecret
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	return &Allocator{secret: secret, maxAttempts: maxAttempts}, nil
+}
+
+// NewAllocatorFromEnv: USER_SERIAL_SECRET / USER_SERIAL_MAX_ATTEMPTS(기본 5) 환경변수로 생성
+func NewAllocatorFromEnv() (*Allocator, error) {
+	attempts := 5
+	if v := os.Getenv("USER_SERIAL_MAX_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			attempts = n
+		}
+	}
+	return NewAllocator([]byte(os.Getenv("USER_SERIAL_SECRET")), attempts)
+}
+
+// MaxAttempts: 충돌 시 재시도 포함 최대 시도 횟수
+func (a *Allocator) MaxAttempts() int {
+	return a.maxAttempts
+}
+
+// Candidate: attempt번째 후보 serial_id (같은 입력/attempt면 항상 같은 값)
+// - 필드 사이에 구분자를 넣어 ("12","3") / ("1","23") 같은 경계 모호성을 없앤다.
+func (a *Allocator) Candidate(studentID, name, phone string, attempt int) int64 {
+	mac := hmac.New(sha256.New, a.secret)
+	fmt.Fprintf(mac, "%s\x00%s\x00%s\x00%d",
+		strings.TrimSpace(studentID), strings.TrimSpace(name), strings.TrimSpace(phone), attempt)
+	sum := mac.Sum(nil)
+	return int64(binary.BigEndian.Uint64(sum[:8]) % space)
+}
+
+// Allocate: 후보를 하나씩 insert 함수에 넘기고, serial_id 충돌이면 다음 후보로 재시도
+// - insert는 후보 serial_id로 INSERT/UPSERT를 수행하고 DB 에러를 그대로 돌려줘야 한다.
+// - 충돌이 아닌 에러는 즉시 반환한다.
+func (a *Allocator) Allocate(studentID, name, phone string, insert func(candidate int64) error) error {
+	for attempt := 0; attempt < a.maxAttempts; attempt++ {
+		err := insert(a.Candidate(studentID, name, phone, attempt))
+		if err == nil {
+			return nil
+		}
+		if !IsCollision(err) {
+			return err
+		}
+	}
+	return ErrExhausted
+}
+
+// IsCollision: users 기본키(serial_id) 유니크 위반인지
+func IsCollision(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "users_pkey"
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"os"
+	"strings"
+)
+
+// 쿠키 전송 모드 관련 상수/설정
+// - AUTH_TOKEN_TRANSPORT=cookie 이면 refresh 토큰은 JSON 바디 대신 HttpOnly 쿠키로만 주고받는다.
+// - 쿠키 모드에서는 브라우저가 쿠키를 자동 첨부하므로 double-submit CSRF 토큰으로 상태 변경 요청을 보호한다.
+const (
+	AccessTokenCookie  = "access_token"  // HttpOnly, Path=/api
+	RefreshTokenCookie = "refresh_token" // HttpOnly, Path=/api/v1/auth
+	CSRFTokenCookie    = "csrf_token"    // JS에서 읽어서 헤더로 다시 보내야 하므로 HttpOnly 아님
+	CSRFHeader         = "X-CSRF-Token"
+
+	AccessCookiePath  = "/api"
+	RefreshCookiePath = "/api/v1/auth"
+)
+
+// CookieTransport: 토큰을 쿠키로 주고받는 모드인지 (기본값: body)
+func CookieTransport() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("AUTH_TOKEN_TRANSPORT")), "cookie")
+}
+
+// CookieSecure: Secure 속성 여부 (기본 true, 로컬 http 개발 시에만 AUTH_COOKIE_SECURE=false)
+func CookieSecure() bool {
+	return EnvBool("AUTH_COOKIE_SECURE", true)
+}
+
+// CookieSameSite: SameSite 속성 (Strict | Lax | None, 기본 Strict)
+func CookieSameSite() string {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("AUTH_COOKIE_SAMESITE"))) {
+	case "lax":
+		return "Lax"
+	case "none":
+		return "None"
+	default:
+		return "Strict"
+	}
+}
+
+// CookieDomain: Domain 속성 (비어 있으면 host-only 쿠키)
+func CookieDomain() string {
+	return strings.TrimSpace(os.Getenv("AUTH_COOKIE_DOMAIN"))
+}
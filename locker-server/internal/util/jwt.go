@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -40,31 +41,35 @@ func GenerateTokens(studentID string) (string, string, error) {
 }
 */
 
-// IssueAccessToken: 학번(studentID)을 sub로 하는 HS256 JWS 발급
+// IssueAccessToken: serial_id를 sub로 하는 HS256 JWS 발급
 // - iss/aud/iat/exp 등 표준 클레임을 채워 넣는다.
+// - sessionID(auth_refresh_tokens.id)를 sid 클레임에 담아 세션 단위 무효화에 사용한다.
+// - 반환값: (토큰 문자열, jti). jti는 세션 테이블에 저장해 두었다가 블랙리스트에 사용.
 // - 운영에서 비대칭(EdDSA)로 바꾸면 공개키 배포/JWKS 도입이 용이.
-func IssueAccessToken(serialID int64, studentID string) (string, error) {
+func IssueAccessToken(serialID int64, studentID string, sessionID int64) (string, string, error) {
 	secret := []byte(os.Getenv("JWT_ACCESS_SECRET")) // 절대 유출 금지
 	iss := os.Getenv("JWT_ISS")                      // 발급자
 	aud := os.Getenv("JWT_AUD")                      // 대상
 
 	// 환경변수 검증
 	if secret == nil || iss == "" || aud == "" {
-		return "", fmt.Errorf("missing required environment variables for JWT")
+		return "", "", fmt.Errorf("missing required environment variables for JWT")
 	}
 	ttlMin := EnvInt("JWT_ACCESS_TTL_MIN", 10) // 만료(분)
 
 	now := time.Now()
+	jti := RandomToken(16)
 
 	// JWT payload(클레임)
 	claims := jwt.MapClaims{
-		"sub":        fmt.Sprint(serialID),                               // 누가(고유 ID)
-		"student_id": studentID,                                         // 학번 (참고용)
+		"sub":        fmt.Sprint(serialID),                                // 누가(고유 ID)
+		"student_id": studentID,                                           // 학번 (참고용)
 		"iss":        iss,                                                 // 누가 발급
 		"aud":        aud,                                                 // 누구에게 유효
 		"iat":        now.Unix(),                                          // 발급 시각
 		"exp":        now.Add(time.Duration(ttlMin) * time.Minute).Unix(), // 만료 시각
-		"jti":        RandomToken(16),                                     // JWT ID (블랙리스트용)
+		"jti":        jti,                                                 // JWT ID (블랙리스트용)
+		"sid":        fmt.Sprint(sessionID),                               // 세션 ID (auth_refresh_tokens.id)
 	}
 
 	// 헤더의 alg는 HS256, typ는 JWT
@@ -73,7 +78,11 @@ func IssueAccessToken(serialID int64, studentID string) (string, error) {
 	tok.Header["kid"] = "hs256-main" // 키 식별자(로테이션 대비, 지금은 고정 값)
 
 	// 서명 후 compact 토큰 문자열 반환
-	return tok.SignedString(secret)
+	signed, err := tok.SignedString(secret)
+	if err != nil {
+		return "", "", err
+	}
+	return signed, jti, nil
 }
 
 // EnvInt: 환경변수를 정수로 읽는 작은 헬퍼 (비었거나 파싱 실패 시 def 반환)
@@ -90,6 +99,20 @@ func EnvInt(key string, def int) int {
 	return x
 }
 
+// EnvBool: 환경변수를 bool로 읽는 작은 헬퍼 (비었거나 파싱 실패 시 def 반환)
+func EnvBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		fmt.Printf("Invalid environment variable %s, using default value %t\n", key, def)
+		return def
+	}
+	return b
+}
+
 // RandomToken: 안전한 랜덤 토큰 생성 (auth.go에서 사용)
 func RandomToken(length int) string {
 	bytes := make([]byte, length)
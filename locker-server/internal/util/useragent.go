@@ -0,0 +1,76 @@
+package util
+
+import "strings"
+
+// DeviceInfo: User-Agent 문자열에서 뽑아낸 대략적인 기기 정보
+// - 세션 목록 화면에서 "어떤 기기에서 로그인했는지" 보여주는 용도 (정확한 판별 X)
+type DeviceInfo struct {
+	Browser string `json:"browser" example:"Chrome"`
+	OS      string `json:"os" example:"Android"`
+	Device  string `json:"device" example:"mobile"` // mobile | tablet | desktop | unknown
+}
+
+// ParseUserAgent: 외부 라이브러리 없이 흔한 패턴만 매칭하는 간단한 UA 파서
+// - 순서가 중요: Edge/Opera/Samsung 등은 UA에 "Chrome"/"Safari"를 함께 포함한다.
+func ParseUserAgent(ua string) DeviceInfo {
+	info := DeviceInfo{Browser: "unknown", OS: "unknown", Device: "unknown"}
+	if strings.TrimSpace(ua) == "" {
+		return info
+	}
+	s := strings.ToLower(ua)
+
+	// 브라우저
+	switch {
+	case strings.Contains(s, "kakaotalk"):
+		info.Browser = "KakaoTalk"
+	case strings.Contains(s, "edg/") || strings.Contains(s, "edge/"):
+		info.Browser = "Edge"
+	case strings.Contains(s, "opr/") || strings.Contains(s, "opera"):
+		info.Browser = "Opera"
+	case strings.Contains(s, "samsungbrowser"):
+		info.Browser = "Samsung Internet"
+	case strings.Contains(s, "whale/"):
+		info.Browser = "Whale"
+	case strings.Contains(s, "firefox/") || strings.Contains(s, "fxios/"):
+		info.Browser = "Firefox"
+	case strings.Contains(s, "chrome/") || strings.Contains(s, "crios/"):
+		info.Browser = "Chrome"
+	case strings.Contains(s, "safari/"):
+		info.Browser = "Safari"
+	case strings.Contains(s, "curl/"):
+		info.Browser = "curl"
+	case strings.Contains(s, "postman"):
+		info.Browser = "Postman"
+	}
+
+	// 운영체제 (iOS 판별을 Mac보다 먼저: iPad UA에도 "Mac OS X"가 들어있음)
+	switch {
+	case strings.Contains(s, "iphone") || strings.Contains(s, "ipad") || strings.Contains(s, "ipod"):
+		info.OS = "iOS"
+	case strings.Contains(s, "android"):
+		info.OS = "Android"
+	case strings.Contains(s, "windows"):
+		info.OS = "Windows"
+	case strings.Contains(s, "mac os x") || strings.Contains(s, "macintosh"):
+		info.OS = "macOS"
+	case strings.Contains(s, "cros"):
+		info.OS = "ChromeOS"
+	case strings.Contains(s, "linux"):
+		info.OS = "Linux"
+	}
+
+	// 기기 종류
+	switch {
+	case strings.Contains(s, "ipad") || strings.Contains(s, "tablet"):
+		info.Device = "tablet"
+	case strings.Contains(s, "mobi") || strings.Contains(s, "iphone"):
+		info.Device = "mobile"
+	case strings.Contains(s, "android"):
+		// 모바일 표기가 없는 Android는 대부분 태블릿
+		info.Device = "tablet"
+	case info.OS == "Windows" || info.OS == "macOS" || info.OS == "Linux" || info.OS == "ChromeOS":
+		info.Device = "desktop"
+	}
+
+	return info
+}